@@ -0,0 +1,287 @@
+package scanner
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// A JSONEscaper supports escape sequences as defined by the JSON specification.
+// Surrogate pairs like \ud83d\ude00 are combined into a single rune,
+// unpaired surrogates are replaced with utf8.RuneError, like encoding/json does.
+type JSONEscaper struct{}
+
+// EscapeIndex implements the Escaper interface
+func (JSONEscaper) EscapeIndex(s string) int { return strings.IndexByte(s, '\\') }
+
+// Unescape implements the Escaper interface
+func (JSONEscaper) Unescape(s string) (string, int, error) {
+	if len(s) < 2 {
+		return "", 0, ErrEscape
+	}
+	switch s[1] {
+	case '"', '\\', '/':
+		return s[1:2], 2, nil
+	case 'b':
+		return "\b", 2, nil
+	case 'f':
+		return "\f", 2, nil
+	case 'n':
+		return "\n", 2, nil
+	case 'r':
+		return "\r", 2, nil
+	case 't':
+		return "\t", 2, nil
+	case 'u':
+		r, ok := parseHex(s[2:], 4)
+		if !ok {
+			return "", 0, ErrEscape
+		}
+		if !utf16.IsSurrogate(r) {
+			return string(r), 6, nil
+		}
+		if len(s) >= 8 && s[6] == '\\' && s[7] == 'u' {
+			if r2, ok := parseHex(s[8:], 4); ok {
+				if c := utf16.DecodeRune(r, r2); c != utf8.RuneError {
+					return string(c), 12, nil
+				}
+			}
+		}
+		return string(utf8.RuneError), 6, nil
+	}
+	return "", 0, ErrEscape
+}
+
+// A CEscaper supports escape sequences as defined by the C standard.
+// Octal and hexadecimal escapes produce a single byte, so their value must be at most 0xFF.
+// Universal character names like \u00e9 produce the UTF-8 encoding of the character.
+type CEscaper struct{}
+
+// EscapeIndex implements the Escaper interface
+func (CEscaper) EscapeIndex(s string) int { return strings.IndexByte(s, '\\') }
+
+// Unescape implements the Escaper interface
+func (CEscaper) Unescape(s string) (string, int, error) {
+	if len(s) < 2 {
+		return "", 0, ErrEscape
+	}
+	if c, ok := simpleEscape(s[1]); ok {
+		return c, 2, nil
+	}
+	switch c := s[1]; {
+	case c == '?':
+		return "?", 2, nil
+	case c >= '0' && c <= '7':
+		v, n := parseOctal(s[1:])
+		if v > 0xFF {
+			return "", 0, ErrEscape
+		}
+		return string([]byte{byte(v)}), 1 + n, nil
+	case c == 'x':
+		n := 2
+		v := 0
+		for ; n < len(s) && isHex(s[n]); n++ {
+			v = v<<4 | hexValue(s[n])
+			if v > 0xFF {
+				return "", 0, ErrEscape
+			}
+		}
+		if n == 2 {
+			return "", 0, ErrEscape
+		}
+		return string([]byte{byte(v)}), n, nil
+	case c == 'u' || c == 'U':
+		return universalName(s)
+	}
+	return "", 0, ErrEscape
+}
+
+// A PythonEscaper supports escape sequences as defined by python 3 for str literals.
+// As in python, unknown escape sequences like \q are kept unchanged, including the backslash.
+// Escapes of the form \N{name} are only supported if Names is not nil.
+type PythonEscaper struct {
+	// Names returns the character with the given unicode name, or false if there is no such character.
+	Names func(name string) (rune, bool)
+}
+
+// EscapeIndex implements the Escaper interface
+func (PythonEscaper) EscapeIndex(s string) int { return strings.IndexByte(s, '\\') }
+
+// Unescape implements the Escaper interface
+func (p PythonEscaper) Unescape(s string) (string, int, error) {
+	if len(s) < 2 {
+		return "\\", 1, nil
+	}
+	if c, ok := simpleEscape(s[1]); ok {
+		return c, 2, nil
+	}
+	switch c := s[1]; {
+	case c >= '0' && c <= '7':
+		v, n := parseOctal(s[1:])
+		return string(rune(v)), 1 + n, nil
+	case c == 'x':
+		r, ok := parseHex(s[2:], 2)
+		if !ok {
+			return "", 0, ErrEscape
+		}
+		return string(r), 4, nil
+	case c == 'u' || c == 'U':
+		return universalName(s)
+	case c == 'N':
+		end := strings.IndexByte(s, '}')
+		if p.Names == nil || len(s) < 3 || s[2] != '{' || end < 0 {
+			return "", 0, ErrEscape
+		}
+		r, ok := p.Names(s[3:end])
+		if !ok {
+			return "", 0, ErrEscape
+		}
+		return string(r), end + 1, nil
+	}
+	return s[:2], 2, nil
+}
+
+// ShellSingle is an Escaper for strings in single quotes in a POSIX shell, which do not have any escape sequences.
+type ShellSingle struct{}
+
+// EscapeIndex implements the Escaper interface
+func (ShellSingle) EscapeIndex(s string) int { return -1 }
+
+// Unescape implements the Escaper interface
+func (ShellSingle) Unescape(s string) (string, int, error) { return "", 0, ErrEscape }
+
+// ShellDouble is an Escaper for strings in double quotes in a POSIX shell.
+// A backslash only escapes '$', '`', '"' and '\', otherwise it is kept unchanged.
+// Line continuations are not supported, a backslash at the end of a line is kept as well.
+type ShellDouble struct{}
+
+// EscapeIndex implements the Escaper interface
+func (ShellDouble) EscapeIndex(s string) int { return strings.IndexByte(s, '\\') }
+
+// Unescape implements the Escaper interface
+func (ShellDouble) Unescape(s string) (string, int, error) {
+	if len(s) >= 2 && strings.IndexByte("$`\"\\", s[1]) >= 0 {
+		return s[1:2], 2, nil
+	}
+	return "\\", 1, nil
+}
+
+// XMLEntityEscaper replaces the predefined XML entities &amp;, &lt;, &gt;, &quot; and &apos;,
+// and character references like &#65; and &#x41;.
+type XMLEntityEscaper struct{}
+
+// EscapeIndex implements the Escaper interface
+func (XMLEntityEscaper) EscapeIndex(s string) int { return strings.IndexByte(s, '&') }
+
+// Unescape implements the Escaper interface
+func (XMLEntityEscaper) Unescape(s string) (string, int, error) {
+	end := strings.IndexByte(s, ';')
+	if end < 0 {
+		return "", 0, ErrEscape
+	}
+	name := s[1:end]
+	switch name {
+	case "amp":
+		return "&", end + 1, nil
+	case "lt":
+		return "<", end + 1, nil
+	case "gt":
+		return ">", end + 1, nil
+	case "quot":
+		return "\"", end + 1, nil
+	case "apos":
+		return "'", end + 1, nil
+	}
+	if !strings.HasPrefix(name, "#") {
+		return "", 0, ErrEscape
+	}
+	var v uint64
+	var err error
+	if strings.HasPrefix(name, "#x") {
+		v, err = strconv.ParseUint(name[2:], 16, 32)
+	} else {
+		v, err = strconv.ParseUint(name[1:], 10, 32)
+	}
+	if err != nil || !isXMLChar(rune(v)) {
+		return "", 0, ErrEscape
+	}
+	return string(rune(v)), end + 1, nil
+}
+
+// isXMLChar returns true if r is allowed in an XML document.
+func isXMLChar(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\r' ||
+		r >= 0x20 && r <= 0xD7FF || r >= 0xE000 && r <= 0xFFFD || r >= 0x10000 && r <= 0x10FFFF
+}
+
+// simpleEscape returns the character for the single-character escapes shared by C and python.
+func simpleEscape(c byte) (string, bool) {
+	switch c {
+	case 'a':
+		return "\a", true
+	case 'b':
+		return "\b", true
+	case 'f':
+		return "\f", true
+	case 'n':
+		return "\n", true
+	case 'r':
+		return "\r", true
+	case 't':
+		return "\t", true
+	case 'v':
+		return "\v", true
+	case '\\', '\'', '"':
+		return string(c), true
+	}
+	return "", false
+}
+
+// universalName parses an escape of the form \uXXXX or \UXXXXXXXX.
+func universalName(s string) (string, int, error) {
+	n := 4
+	if s[1] == 'U' {
+		n = 8
+	}
+	r, ok := parseHex(s[2:], n)
+	if !ok || r < 0 || r > utf8.MaxRune || utf16.IsSurrogate(r) {
+		return "", 0, ErrEscape
+	}
+	return string(r), 2 + n, nil
+}
+
+// parseOctal parses up to three octal digits at the start of s.
+func parseOctal(s string) (int, int) {
+	v, n := 0, 0
+	for ; n < 3 && n < len(s) && s[n] >= '0' && s[n] <= '7'; n++ {
+		v = v<<3 | int(s[n]-'0')
+	}
+	return v, n
+}
+
+// parseHex parses exactly n hexadecimal digits at the start of s.
+func parseHex(s string, n int) (rune, bool) {
+	if len(s) < n {
+		return 0, false
+	}
+	var r rune
+	for i := 0; i < n; i++ {
+		if !isHex(s[i]) {
+			return 0, false
+		}
+		r = r<<4 | rune(hexValue(s[i]))
+	}
+	return r, true
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c|0x20 >= 'a' && c|0x20 <= 'f'
+}
+
+func hexValue(c byte) int {
+	if c <= '9' {
+		return int(c - '0')
+	}
+	return int(c|0x20-'a') + 10
+}
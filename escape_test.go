@@ -0,0 +1,150 @@
+package scanner_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	. "github.com/jfreymuth/scanner"
+)
+
+func TestJSONEscaper(t *testing.T) {
+	tests := []string{
+		`plain`,
+		`a\"b`,
+		`a\\b\/c`,
+		`\b\f\n\r\t`,
+		`Aé€`,
+		`😀`,
+		`\ud83d`,
+		`\ude00x`,
+		`\ud83dA`,
+		`\u12`,
+		`\u12g4`,
+		`\x41`,
+		`\'`,
+		`\a`,
+		`\`,
+	}
+
+	for _, in := range tests {
+		var ref string
+		refErr := json.Unmarshal([]byte(`"`+in+`"`), &ref)
+		sc := FromString(`"` + in + `"`)
+		out := sc.Quote(`"`, `"`, JSONEscaper{})
+		if refErr != nil {
+			if sc.Err() == nil {
+				t.Errorf("input %q should produce an error", in)
+			}
+		} else if sc.Err() != nil {
+			t.Errorf("input %q produced error: %s", in, sc.Err())
+		} else if out != ref {
+			t.Errorf("input %q produced output %q instead of %q", in, out, ref)
+		}
+	}
+}
+
+func TestXMLEntityEscaper(t *testing.T) {
+	tests := []string{
+		`plain`,
+		`a &amp; b`,
+		`&lt;&gt;&quot;&apos;`,
+		`&#65;&#x41;&#x1F600;`,
+		`&#0;`,
+		`&#x110000;`,
+		`&#;`,
+		`&#x;`,
+		`&#X41;`,
+		`&nbsp;`,
+		`a & b`,
+		`&amp`,
+	}
+
+	for _, in := range tests {
+		var ref struct {
+			S string `xml:",chardata"`
+		}
+		refErr := xml.Unmarshal([]byte("<a>"+in+"</a>"), &ref)
+		sc := FromString("<a>" + in + "</a>")
+		out := sc.Quote("<a>", "</a>", XMLEntityEscaper{})
+		if refErr != nil {
+			if sc.Err() == nil {
+				t.Errorf("input %q should produce an error", in)
+			}
+		} else if sc.Err() != nil {
+			t.Errorf("input %q produced error: %s", in, sc.Err())
+		} else if out != ref.S {
+			t.Errorf("input %q produced output %q instead of %q", in, out, ref.S)
+		}
+	}
+}
+
+func TestEscapers(t *testing.T) {
+	names := func(name string) (rune, bool) {
+		if name == "BULLET" {
+			return '•', true
+		}
+		return 0, false
+	}
+	tests := []struct {
+		in  string
+		esc Escaper
+		out string
+		ok  bool
+	}{
+		// results of gcc
+		{`a\nb\tc`, CEscaper{}, "a\nb\tc", true},
+		{`\a\b\f\v\r`, CEscaper{}, "\a\b\f\v\r", true},
+		{`\'\"\?\\`, CEscaper{}, `'"?\`, true},
+		{`\101\0\12a`, CEscaper{}, "A\x00\na", true},
+		{`\1234`, CEscaper{}, "S4", true},
+		{`\400`, CEscaper{}, "", false},
+		{`\x41\xff\x00041`, CEscaper{}, "A\xffA", true},
+		{`\x100`, CEscaper{}, "", false},
+		{`\x`, CEscaper{}, "", false},
+		{`é\U0001F600`, CEscaper{}, "é😀", true},
+		{`\ud800`, CEscaper{}, "", false},
+		{`\q`, CEscaper{}, "", false},
+		{`\8`, CEscaper{}, "", false},
+
+		// results of python 3
+		{`a\nb\tc`, PythonEscaper{}, "a\nb\tc", true},
+		{`\a\b\f\v\r`, PythonEscaper{}, "\a\b\f\v\r", true},
+		{`\'\"\\`, PythonEscaper{}, `'"\`, true},
+		{`\x41\101\0\12a`, PythonEscaper{}, "AA\x00\na", true},
+		{`\777`, PythonEscaper{}, "ǿ", true},
+		{`\x4`, PythonEscaper{}, "", false},
+		{`é\U0001F600`, PythonEscaper{}, "é😀", true},
+		{`\U00110000`, PythonEscaper{}, "", false},
+		{`\q\8\?`, PythonEscaper{}, `\q\8\?`, true},
+		{`\N{BULLET}`, PythonEscaper{names}, "•", true},
+		{`\N{NO SUCH NAME}`, PythonEscaper{names}, "", false},
+		{`\N{BULLET}`, PythonEscaper{}, "", false},
+		{`\N`, PythonEscaper{names}, "", false},
+
+		// results of a POSIX shell
+		{`a\$b\` + "`" + `c`, ShellDouble{}, "a$b`c", true},
+		{`a\\b\"c`, ShellDouble{}, `a\b"c`, true},
+		{`a\nb\'c`, ShellDouble{}, `a\nb\'c`, true},
+		{`a\nb\$c`, ShellSingle{}, `a\nb\$c`, true},
+
+		// encoding/xml accepts references to surrogates, but the XML specification does not
+		{`&#xD800;`, XMLEntityEscaper{}, "", false},
+	}
+
+	for _, test := range tests {
+		sc := FromString("<" + test.in + ">")
+		out := sc.Quote("<", ">", test.esc)
+		if test.ok {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if out != test.out {
+				t.Errorf("input %q produced output %q instead of %q", test.in, out, test.out)
+			}
+		} else {
+			if sc.Err() == nil {
+				t.Errorf("input %q should produce an error", test.in)
+			}
+		}
+	}
+}
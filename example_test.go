@@ -47,7 +47,7 @@ func Example() {
 
 func parseValue(sc *scanner.Scanner) interface{} {
 	switch {
-	case sc.Is("\""):
+	case sc.IsString():
 		return sc.String()
 	case sc.Eat("["):
 		var list []interface{}
@@ -64,7 +64,7 @@ func parseValue(sc *scanner.Scanner) interface{} {
 	case sc.IsFloat():
 		return sc.Float()
 	default:
-		sc.Fail("invalid value")
+		sc.FailExpected()
 		return nil
 	}
 }
@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expect records that the scanner's input could have continued with what at the current position.
+// Methods like Is, IsIdent or IsInt call Expect automatically,
+// so that FailExpected can report every alternative that was tried.
+// Methods that consume input, like Eat, only record their token if it does not match.
+// what should be a description like "number" or a quoted token like "'('".
+func (s *Scanner) Expect(what string) {
+	s.expect(expectation{what: what})
+}
+
+// An expectation is an alternative recorded by Expect.
+// Tokens and operator sets are only formatted by Expected, so that recording them does not allocate.
+type expectation struct {
+	what   string
+	quoted bool         // what is a token that is formatted in quotes
+	set    *OperatorSet // all operators of the set, formatted in quotes
+}
+
+// expectToken records the token str as expected.
+func (s *Scanner) expectToken(str string) {
+	s.expect(expectation{what: str, quoted: true})
+}
+
+func (s *Scanner) expect(e expectation) {
+	if off := s.off + s.pos; off != s.expOff || s.expected == nil {
+		s.expected = s.expected[:0]
+		s.expOff = off
+	}
+	for _, x := range s.expected {
+		if x == e {
+			return
+		}
+	}
+	s.expected = append(s.expected, e)
+}
+
+// Expected returns the alternatives that were recorded by Expect at the current position.
+func (s *Scanner) Expected() []string {
+	if s.off+s.pos != s.expOff {
+		return nil
+	}
+	var list []string
+	add := func(what string) {
+		for _, x := range list {
+			if x == what {
+				return
+			}
+		}
+		list = append(list, what)
+	}
+	for _, e := range s.expected {
+		switch {
+		case e.set != nil:
+			for _, op := range e.set.ops {
+				add("'" + op + "'")
+			}
+		case e.quoted:
+			add("'" + e.what + "'")
+		default:
+			add(e.what)
+		}
+	}
+	return list
+}
+
+// FailExpected causes an error listing the alternatives that were recorded by Expect at the current position,
+// for example "expected '[', string or integer, found 'x'".
+func (s *Scanner) FailExpected() {
+	if exp := s.Expected(); len(exp) > 0 {
+		s.Failf("expected %s, found %s", joinOr(exp), s.found())
+	} else {
+		s.Failf("unexpected %s", s.found())
+	}
+}
+
+// found describes the next token for use in error messages.
+func (s *Scanner) found() string {
+	if s.err != nil || s.syncing {
+		return "end of input"
+	}
+	if id := s.peekWord(); id != "" {
+		return "'" + id + "'"
+	}
+	return fmt.Sprintf("'%c'", s.r)
+}
+
+// joinOr formats a list of alternatives like "a, b or c".
+func joinOr(list []string) string {
+	if len(list) == 1 {
+		return list[0]
+	}
+	return strings.Join(list[:len(list)-1], ", ") + " or " + list[len(list)-1]
+}
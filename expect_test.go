@@ -0,0 +1,69 @@
+package scanner_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/jfreymuth/scanner"
+)
+
+func TestFailExpected(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"x", "expected string, '[', integer or float, found 'x'"},
+		{"[1, x]", "expected ']', string, '[', integer or float, found 'x'"},
+		{"[1 x]", "']' expected"},
+		{"[1,", "expected ']', string, '[', integer or float, found end of input"},
+		{"?", "expected string, '[', integer or float, found '?'"},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		parseValue(sc)
+		if err, ok := sc.Err().(*Error); !ok {
+			t.Errorf("input %q produced error %v", test.in, sc.Err())
+		} else if err.Message != test.out {
+			t.Errorf("input %q produced error %q instead of %q", test.in, err.Message, test.out)
+		}
+	}
+}
+
+func TestExpected(t *testing.T) {
+	sc := FromString("a b")
+	sc.Is("(")
+	sc.IsInt()
+	sc.Is("(")
+	sc.Expect("thing")
+	if exp := fmt.Sprint(sc.Expected()); exp != "['(' integer thing]" {
+		t.Errorf("recorded %s instead of ['(' integer thing]", exp)
+	}
+	sc.Ident()
+	if exp := sc.Expected(); len(exp) != 0 {
+		t.Errorf("recorded %q after advancing", exp)
+	}
+	sc.Is(")")
+	sc.FailExpected()
+	if msg := sc.Err().(*Error).Message; msg != "expected ')', found 'b'" {
+		t.Errorf("produced error %q instead of %q", msg, "expected ')', found 'b'")
+	}
+}
+
+func TestExpectAllocs(t *testing.T) {
+	ops := NewOperatorSet("<", "<=", "<<", "<<=")
+	sc := FromString("a")
+	allocs := testing.AllocsPerRun(100, func() {
+		sc.Is("(")
+		sc.Eat("(")
+		sc.EatKeyword("if")
+		sc.EatAny("+", "-")
+		sc.EatOperator(ops)
+	})
+	if allocs != 0 {
+		t.Errorf("recording expected tokens caused %v allocations", allocs)
+	}
+	if exp := fmt.Sprint(sc.Expected()); exp != "['(' 'if' '+' '-' '<' '<=' '<<' '<<=']" {
+		t.Errorf("recorded %s", exp)
+	}
+}
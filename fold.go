@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// IsFold is like Is, but ignores case, using Unicode simple case folding like strings.EqualFold.
+func (s *Scanner) IsFold(str string) bool {
+	s.expectToken(str)
+	return prefixFold(s.line[s.pos:], str) >= 0
+}
+
+// EatFold is like Eat, but ignores case, using Unicode simple case folding like strings.EqualFold.
+func (s *Scanner) EatFold(str string) bool {
+	return s.eatMatch(str, true)
+}
+
+// DemandFold is like Demand, but ignores case, using Unicode simple case folding like strings.EqualFold.
+// The error message uses the spelling of str.
+func (s *Scanner) DemandFold(str string) {
+	if !s.EatFold(str) {
+		s.Failf("'%s' expected", str)
+	}
+}
+
+// eatMatch consumes str, ignoring case if fold is true, and returns true if it was found.
+func (s *Scanner) eatMatch(str string, fold bool) bool {
+	n := len(str)
+	if fold {
+		n = prefixFold(s.line[s.pos:], str)
+	} else if !s.has(str) {
+		n = -1
+	}
+	if n < 0 {
+		s.expectToken(str)
+		return false
+	}
+	s.pos += n
+	s.space()
+	return true
+}
+
+// prefixFold returns the length of the prefix of str that is equal to prefix under case folding,
+// or -1 if there is none.
+func prefixFold(str, prefix string) int {
+	i := 0
+	for _, p := range prefix {
+		if i >= len(str) {
+			return -1
+		}
+		r, size := utf8.DecodeRuneInString(str[i:])
+		if r != p && foldRune(r) != foldRune(p) {
+			return -1
+		}
+		i += size
+	}
+	return i
+}
+
+// foldKey returns a string that is equal for all strings that are equal under case folding.
+func foldKey(str string) string {
+	return strings.Map(foldRune, str)
+}
+
+// foldRune returns the smallest rune that is equivalent to r under simple case folding.
+func foldRune(r rune) rune {
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	return min
+}
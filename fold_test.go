@@ -0,0 +1,87 @@
+package scanner_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/jfreymuth/scanner"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		in      string
+		demands []string
+		ok      bool
+	}{
+		{"select", []string{"SELECT"}, true},
+		{"SeLeCt *", []string{"select", "*"}, true},
+		{"straße", []string{"STRAßE"}, true},
+		{"ǅ", []string{"ǆ"}, true},
+		{"K", []string{"k"}, true},
+		{"kx", []string{"K", "X"}, true},
+		{"σς", []string{"ΣΣ"}, true},
+		{"sel", []string{"select"}, false},
+		{"selekt", []string{"select"}, false},
+	}
+
+	for _, test := range tests {
+		for _, option := range []bool{false, true} {
+			var sc *Scanner
+			if option {
+				sc = NewWithOptions(strings.NewReader(test.in), Options{FoldCase: true})
+			} else {
+				sc = FromString(test.in)
+			}
+			if sc.IsFold(test.demands[0]) != test.ok {
+				t.Errorf("input %q: IsFold(%q) returned %v", test.in, test.demands[0], !test.ok)
+			}
+			if option && sc.Is(test.demands[0]) != test.ok {
+				t.Errorf("input %q: Is(%q) returned %v with FoldCase", test.in, test.demands[0], !test.ok)
+			}
+			for _, d := range test.demands {
+				if option {
+					sc.Demand(d)
+				} else {
+					sc.DemandFold(d)
+				}
+			}
+			if test.ok {
+				if sc.Err() != nil {
+					t.Errorf("input %q, %q produced error: %s", test.in, test.demands, sc.Err())
+				} else if !sc.End() {
+					t.Errorf("input %q, %q: End returned false", test.in, test.demands)
+				}
+			} else if sc.Err() == nil {
+				t.Errorf("input %q, %q should produce an error", test.in, test.demands)
+			}
+		}
+	}
+
+	sc := FromString("select")
+	if sc.Eat("SELECT") {
+		t.Error("Eat ignored case without FoldCase")
+	}
+	sc.DemandFold("FROM")
+	if msg := sc.Err().(*Error).Message; msg != "'FROM' expected" {
+		t.Errorf("produced error %q instead of \"'FROM' expected\"", msg)
+	}
+}
+
+func TestFoldKeyword(t *testing.T) {
+	opt := Options{FoldCase: true, Keywords: []string{"SELECT", "from"}}
+	sc := NewWithOptions(strings.NewReader("Select x FROM Selection"), opt)
+	sc.DemandKeyword("select")
+	if id := sc.Ident(); id != "x" {
+		t.Errorf("produced identifier %q instead of \"x\"", id)
+	}
+	if sc.IsIdent() {
+		t.Error("IsIdent accepted keyword \"FROM\"")
+	}
+	sc.DemandKeyword("From")
+	if id := sc.Ident(); id != "Selection" {
+		t.Errorf("produced identifier %q instead of \"Selection\"", id)
+	}
+	if sc.Err() != nil {
+		t.Errorf("produced error: %s", sc.Err())
+	}
+}
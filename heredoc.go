@@ -0,0 +1,140 @@
+package scanner
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// IsHeredoc returns true if the next token is the start of a heredoc.
+func (s *Scanner) IsHeredoc() bool {
+	s.Expect("heredoc")
+	_, _, _, ok := heredocStart(s.line[s.pos:])
+	return ok
+}
+
+// Heredoc parses a shell-style here document. The start token is "<<", followed by a terminator,
+// which is a word consisting of letters, digits and underscores, optionally enclosed in single or double quotes.
+// The heredoc's text consists of the lines following the current one, up to a line that is exactly the terminator.
+// The rest of the current line is parsed normally, and the text and terminator line are skipped once the scanner
+// reaches the end of the current line. If there are several heredocs on the same line, their texts follow each other.
+//
+// The start token may also be "<<-", which removes leading tabs from every line, including the terminator line,
+// or "<<~", which allows the terminator line to be indented and removes the common indentation from the text.
+// The returned text includes a line break after every line.
+func (s *Scanner) Heredoc() string {
+	n, term, mode, ok := heredocStart(s.line[s.pos:])
+	if !ok {
+		s.Fail("heredoc expected")
+		return ""
+	}
+	if s.marks == 0 {
+		s.trim()
+	}
+	var lines []string
+	for i := s.skip; ; i++ {
+		line, ok := s.peekLine(i)
+		if !ok {
+			s.Failf("'%s' expected", term)
+			return ""
+		}
+		text := line.text
+		switch mode {
+		case '-':
+			text = strings.TrimLeft(text, "\t")
+		case '~':
+			if strings.TrimLeft(text, " \t") == term {
+				text = term
+			}
+		}
+		if text == term {
+			break
+		}
+		lines = append(lines, text)
+	}
+	s.skip += len(lines) + 1
+	if mode == '~' {
+		lines = dedent(lines)
+	}
+	s.pos += n
+	s.space()
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// heredocStart returns the length of the heredoc start token at the beginning of line,
+// the terminator, and the character following "<<" if it is '-' or '~'.
+func heredocStart(line string) (int, string, byte, bool) {
+	if !strings.HasPrefix(line, "<<") {
+		return 0, "", 0, false
+	}
+	i := 2
+	var mode byte
+	if i < len(line) && (line[i] == '-' || line[i] == '~') {
+		mode = line[i]
+		i++
+	}
+	if i < len(line) && (line[i] == '\'' || line[i] == '"') {
+		end := strings.IndexByte(line[i+1:], line[i])
+		if end <= 0 {
+			return 0, "", 0, false
+		}
+		return i + end + 2, line[i+1 : i+1+end], mode, true
+	}
+	start := i
+	for i < len(line) {
+		r, size := utf8.DecodeRuneInString(line[i:])
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+		i += size
+	}
+	if i == start {
+		return 0, "", 0, false
+	}
+	return i, line[start:i], mode, true
+}
+
+// peekLine returns the i-th line following the current one, without advancing the scanner.
+// The line is kept in the replay buffer until the scanner reads it.
+// The replay buffer must have been trimmed if there are no marks.
+func (s *Scanner) peekLine(i int) (bufLine, bool) {
+	k := s.ln - s.bufLn + i
+	for len(s.buf) <= k {
+		off := s.srcOff
+		text, ok := s.readSource(s.bufLn + len(s.buf) + 1)
+		if !ok {
+			return bufLine{}, false
+		}
+		s.buf = append(s.buf, bufLine{text, off})
+	}
+	return s.buf[k], true
+}
+
+// dedent removes the longest common prefix of spaces and tabs from all lines.
+// Lines that consist only of whitespace are ignored when determining the prefix, and are returned as empty lines.
+func dedent(lines []string) []string {
+	prefix, first := "", true
+	for _, l := range lines {
+		indent := l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+		if indent == l {
+			continue
+		}
+		if first {
+			prefix, first = indent, false
+			continue
+		}
+		for !strings.HasPrefix(indent, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		if strings.TrimLeft(l, " \t") != "" {
+			out[i] = l[len(prefix):]
+		}
+	}
+	return out
+}
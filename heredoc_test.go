@@ -0,0 +1,100 @@
+package scanner_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/jfreymuth/scanner"
+)
+
+func TestHeredoc(t *testing.T) {
+	tests := []struct {
+		in   string
+		out  string
+		next string
+		ok   bool
+	}{
+		{"<<EOF\nline 1\n  line 2\nEOF\nnext", "line 1\n  line 2\n", "next", true},
+		{"<<EOF next\nline\nEOF", "line\n", "next", true},
+		{"<<EOF\nEOF\nnext", "", "next", true},
+		{"<<'END'\n$x\nEND\nnext", "$x\n", "next", true},
+		{"<<\"END\"\nx\nEND\nnext", "x\n", "next", true},
+		{"<<EOF\n EOF\nEOF x\nEOF\nnext", " EOF\nEOF x\n", "next", true},
+		{"<<-EOF\n\tline 1\n\t\tline 2\n\tEOF\nnext", "line 1\nline 2\n", "next", true},
+		{"<<~EOF\n    line 1\n      line 2\n\n    line 3\n  EOF\nnext", "line 1\n  line 2\n\nline 3\n", "next", true},
+		{"<<~EOF\n\tline 1\n\t line 2\n\tEOF\nnext", "line 1\n line 2\n", "next", true},
+		{"<<EOF\nline\n", "", "", false},
+		{"<<EOF\nline\n  EOF\n", "", "", false},
+		{"<< EOF\nEOF", "", "", false},
+		{"<<''\n\n", "", "", false},
+		{"EOF", "", "", false},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		if sc.IsHeredoc() != test.ok && test.ok {
+			t.Errorf("input %q: IsHeredoc returned false", test.in)
+		}
+		out := sc.Heredoc()
+		if test.ok {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if out != test.out {
+				t.Errorf("input %q produced output %q instead of %q", test.in, out, test.out)
+			} else if next := sc.Ident(); next != test.next {
+				t.Errorf("input %q: next token is %q instead of %q", test.in, next, test.next)
+			}
+		} else {
+			if sc.Err() == nil {
+				t.Errorf("input %q should produce an error", test.in)
+			}
+		}
+	}
+}
+
+func TestHeredocLine(t *testing.T) {
+	sc := FromString("cat <<A <<B; echo\na\nA\nb\nB\nnext")
+	sc.Ident()
+	a := sc.Heredoc()
+	b := sc.Heredoc()
+	sc.Demand(";")
+	sc.Ident()
+	if a != "a\n" || b != "b\n" {
+		t.Errorf("produced %q and %q", a, b)
+	}
+	if next, pos := sc.PeekIdent(), sc.Pos(); next != "next" || pos.Line != 6 || pos.Offset != 26 {
+		t.Errorf("next token is %q at %v", next, pos)
+	}
+
+	sc = FromString("x <<EOF y\nbody\nEOF\nz")
+	sc.Ident()
+	m := sc.Mark()
+	sc.Heredoc()
+	sc.Ident()
+	sc.Reset(m)
+	sc.Release(m)
+	if !sc.Is("<<") {
+		t.Errorf("Reset did not restore the heredoc")
+	}
+	sc.Demand("<<")
+	var ids []string
+	for !sc.End() {
+		ids = append(ids, sc.Ident())
+	}
+	if len(ids) != 5 || ids[2] != "body" || ids[4] != "z" {
+		t.Errorf("produced %q after Reset", ids)
+	}
+}
+
+func TestHeredocLongLine(t *testing.T) {
+	sc := NewWithOptions(strings.NewReader("x <<EOF\na\nb\n"+strings.Repeat("c", 30)+"\nEOF\n"), Options{MaxLineLength: 20})
+	sc.Ident()
+	sc.Heredoc()
+	err, ok := sc.Err().(*Error)
+	if !ok || err.Message != "line too long" {
+		t.Fatalf("produced error %v", sc.Err())
+	}
+	if p := (Position{"", 32, 4, 21}); err.Position != p {
+		t.Errorf("produced error at %v (offset %d) instead of %v (offset %d)", err.Position, err.Offset, p, p.Offset)
+	}
+}
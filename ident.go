@@ -1,23 +1,97 @@
 package scanner
 
 import (
+	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
+// IdentRules define which runes an identifier may consist of.
+type IdentRules struct {
+	// Start reports whether an identifier may start with r.
+	Start func(r rune) bool
+	// Part reports whether r may appear in an identifier after the first rune. If Part is nil, Start is used.
+	Part func(r rune) bool
+	// Suffix, if not nil, reports whether r may appear as the last rune of an identifier.
+	// It is only consulted for runes that are not accepted by Part.
+	Suffix func(r rune) bool
+}
+
+// Predefined identifier rules.
+var (
+	// GoIdent accepts a letter or underscore, followed by letters, numbers and underscores.
+	// These are the default rules.
+	GoIdent = IdentRules{Start: isGoStart, Part: isGoPart}
+	// RubyIdent is like GoIdent, but identifiers may end in '?' or '!'.
+	RubyIdent = IdentRules{Start: isGoStart, Part: isGoPart, Suffix: isRubySuffix}
+	// LispIdent accepts letters, digits and the characters !$%&*/:<=>?^_~+-.@
+	// Identifiers may not start with a digit or '@', so symbols like +, - and ... are identifiers.
+	// Numbers like -1 or .5 are identifiers as well, a parser should check for numbers first, like a lisp reader does.
+	LispIdent = IdentRules{Start: isLispStart, Part: isLispPart}
+	// CSSIdent accepts a letter, underscore or non-ASCII character, followed by those, digits and hyphens.
+	// Identifiers starting with a hyphen are not supported.
+	CSSIdent = IdentRules{Start: isCSSStart, Part: isCSSPart}
+	// UAX31Ident accepts identifiers as defined by Unicode Standard Annex #31,
+	// an XID_Start character followed by XID_Continue characters.
+	UAX31Ident = IdentRules{Start: isXIDStart, Part: isXIDContinue}
+)
+
+func isGoStart(r rune) bool { return r == '_' || unicode.IsLetter(r) }
+func isGoPart(r rune) bool  { return r == '_' || unicode.IsLetter(r) || unicode.IsNumber(r) }
+
+func isRubySuffix(r rune) bool { return r == '?' || r == '!' }
+
+func isLispStart(r rune) bool {
+	return unicode.IsLetter(r) || r < utf8.RuneSelf && strings.IndexByte("!$%&*/:<=>?^_~+-.", byte(r)) >= 0
+}
+func isLispPart(r rune) bool {
+	return isLispStart(r) || unicode.IsDigit(r) || r == '@'
+}
+
+func isCSSStart(r rune) bool { return r == '_' || unicode.IsLetter(r) || r >= utf8.RuneSelf }
+func isCSSPart(r rune) bool  { return isCSSStart(r) || r >= '0' && r <= '9' || r == '-' }
+
+func isXIDStart(r rune) bool {
+	return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start) && !unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space)
+}
+func isXIDContinue(r rune) bool {
+	return isXIDStart(r) || unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue) && !unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space)
+}
+
 // IsIdent returns true if the next token is an identifier.
+// Words listed in the Keywords option are not identifiers.
 func (s *Scanner) IsIdent() bool {
-	return s.r == '_' || unicode.IsLetter(s.r)
+	s.Expect("identifier")
+	return s.PeekIdent() != ""
+}
+
+func (s *Scanner) isIdent() bool {
+	return s.opt.Ident.Start(s.r)
 }
 
 // PeekIdent returns the next token, or an empty string if the next token is not an identifier.
 // PeekIdent will not advance the scanner.
 func (s *Scanner) PeekIdent() string {
-	if !s.IsIdent() {
+	w := s.peekWord()
+	if s.isKeyword(w) {
 		return ""
 	}
-	for i, r := range s.line[s.pos:] {
-		if r != '_' && !unicode.IsLetter(r) && !unicode.IsNumber(r) {
-			return s.line[s.pos : s.pos+i]
+	return w
+}
+
+// peekWord is like PeekIdent, but does not exclude keywords.
+func (s *Scanner) peekWord() string {
+	if !s.isIdent() {
+		return ""
+	}
+	id := &s.opt.Ident
+	for i, r := range s.line[s.pos+s.size:] {
+		if !id.Part(r) {
+			i += s.pos + s.size
+			if id.Suffix != nil && id.Suffix(r) {
+				i += utf8.RuneLen(r)
+			}
+			return s.line[s.pos:i]
 		}
 	}
 	result := s.line[s.pos:]
@@ -28,9 +102,95 @@ func (s *Scanner) PeekIdent() string {
 func (s *Scanner) Ident() string {
 	result := s.PeekIdent()
 	if result == "" {
-		s.Fail("identifier expected")
+		if w := s.peekWord(); w != "" {
+			s.Failf("identifier expected, found keyword '%s'", w)
+		} else {
+			s.Fail("identifier expected")
+		}
 	}
 	s.pos += len(result)
 	s.space()
 	return result
 }
+
+// QualifiedIdent parses one or more identifiers separated by sep, like "a.b.c" or "pkg::Type::member",
+// and returns the identifiers without the separators.
+// If the QualifiedNoSpace option is set, no whitespace or comments are allowed around the separators.
+// If an identifier is missing after a separator, the error refers to the position of the missing identifier.
+func (s *Scanner) QualifiedIdent(sep string) []string {
+	var parts []string
+	for {
+		id := s.PeekIdent()
+		if id == "" {
+			if len(parts) == 0 {
+				s.Ident()
+			} else if w := s.peekWord(); w != "" {
+				s.Failf("identifier expected after '%s%s', found keyword '%s'", strings.Join(parts, sep), sep, w)
+			} else {
+				s.Failf("identifier expected after '%s%s'", strings.Join(parts, sep), sep)
+			}
+			return nil
+		}
+		parts = append(parts, id)
+		s.pos += len(id)
+		if s.opt.QualifiedNoSpace {
+			if !s.has(sep) {
+				s.space()
+				return parts
+			}
+			s.pos += len(sep)
+			s.r, s.size = utf8.DecodeRuneInString(s.line[s.pos:])
+		} else {
+			s.space()
+			if !s.Is(sep) {
+				return parts
+			}
+			s.pos += len(sep)
+			s.space()
+		}
+	}
+}
+
+// IsKeyword returns true if the next token is an identifier equal to word.
+// Unlike Is, IsKeyword does not match if word is only the beginning of a longer identifier.
+// word does not need to be listed in the Keywords option.
+func (s *Scanner) IsKeyword(word string) bool {
+	s.expectToken(word)
+	return s.keywordLen(word) > 0
+}
+
+// EatKeyword returns true and consumes the keyword if the next token is an identifier equal to word.
+// The scanner will not be advanced if EatKeyword returns false.
+func (s *Scanner) EatKeyword(word string) bool {
+	if n := s.keywordLen(word); n > 0 {
+		s.pos += n
+		s.space()
+		return true
+	}
+	s.expectToken(word)
+	return false
+}
+
+// keywordLen returns the length of the next token if it is equal to word, or 0.
+func (s *Scanner) keywordLen(word string) int {
+	w := s.peekWord()
+	if word == "" || w != word && !(s.opt.FoldCase && strings.EqualFold(w, word)) {
+		return 0
+	}
+	return len(w)
+}
+
+// DemandKeyword consumes a keyword, or causes an error if the next token is not an identifier equal to word.
+func (s *Scanner) DemandKeyword(word string) {
+	if !s.EatKeyword(word) {
+		s.Failf("'%s' expected", word)
+	}
+}
+
+// isKeyword returns true if word is listed in the Keywords option.
+func (s *Scanner) isKeyword(word string) bool {
+	if s.opt.FoldCase {
+		return s.keywords[foldKey(word)]
+	}
+	return s.keywords[word]
+}
@@ -1,7 +1,9 @@
 package scanner_test
 
 import (
+	"strings"
 	"testing"
+	"unicode"
 
 	. "github.com/jfreymuth/scanner"
 )
@@ -83,3 +85,182 @@ func TestWhitespace(t *testing.T) {
 		}
 	}
 }
+
+func TestKeyword(t *testing.T) {
+	tests := []struct {
+		in      string
+		keyword string
+		ok      bool
+	}{
+		{"in", "in", true},
+		{"in x", "in", true},
+		{"in(x)", "in", true},
+		{"index", "in", false},
+		{"in_", "in", false},
+		{"in1", "in", false},
+		{"x in", "in", false},
+		{"", "in", false},
+		{"", "", false},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		if sc.IsKeyword(test.keyword) != test.ok {
+			t.Errorf("input %q: IsKeyword(%q) returned %v", test.in, test.keyword, !test.ok)
+		}
+		sc.DemandKeyword(test.keyword)
+		if test.ok {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			}
+		} else if sc.Err() == nil {
+			t.Errorf("input %q should produce an error", test.in)
+		}
+	}
+}
+
+func TestReservedWords(t *testing.T) {
+	opt := Options{Keywords: []string{"if", "else"}}
+	tests := []struct {
+		in    string
+		out   string
+		valid bool
+	}{
+		{"a", "a", true},
+		{"iff", "iff", true},
+		{"elsewhere", "elsewhere", true},
+		{"if", "", false},
+		{"else x", "", false},
+	}
+
+	for _, test := range tests {
+		sc := NewWithOptions(strings.NewReader(test.in), opt)
+		if sc.IsIdent() != test.valid {
+			t.Errorf("input %q: IsIdent returned %v", test.in, !test.valid)
+		}
+		out := sc.Ident()
+		if test.valid {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if out != test.out {
+				t.Errorf("input %q produced output %q instead of %q", test.in, out, test.out)
+			}
+		} else if sc.Err() == nil {
+			t.Errorf("input %q should produce an error", test.in)
+		}
+	}
+
+	sc := NewWithOptions(strings.NewReader("if x"), opt)
+	sc.Ident()
+	if msg := sc.Err().(*Error).Message; msg != "identifier expected, found keyword 'if'" {
+		t.Errorf("produced error %q", msg)
+	}
+	sc = NewWithOptions(strings.NewReader("if x"), opt)
+	if !sc.EatKeyword("if") || sc.Ident() != "x" || sc.Err() != nil {
+		t.Errorf("keyword followed by identifier produced error %v", sc.Err())
+	}
+}
+
+func TestIdentRules(t *testing.T) {
+	sigil := IdentRules{
+		Start: func(r rune) bool { return r == '$' || r == '@' },
+		Part:  func(r rune) bool { return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) },
+	}
+	tests := []struct {
+		in    string
+		rules IdentRules
+		out   []string
+	}{
+		{"a1 _b", GoIdent, []string{"a1", "_b"}},
+		{"a² b", GoIdent, []string{"a²", "b"}},
+		{"empty? save! a?b", RubyIdent, []string{"empty?", "save!", "a?", "b"}},
+		{"a?? b", RubyIdent, []string{"a?"}},
+		{"foo-bar *x* set! <=> a.b", LispIdent, []string{"foo-bar", "*x*", "set!", "<=>", "a.b"}},
+		{"+ - ... -> -x", LispIdent, []string{"+", "-", "...", "->", "-x"}},
+		{"1+", LispIdent, nil},
+		{"@x", LispIdent, nil},
+		{"foo-bar _x ümlaut h1", CSSIdent, []string{"foo-bar", "_x", "ümlaut", "h1"}},
+		{"abc x́y ǅ Ⅻ", UAX31Ident, []string{"abc", "x́y", "ǅ", "Ⅻ"}},
+		{"_x", UAX31Ident, nil},
+		{"́x", UAX31Ident, nil},
+		{"$var @attr", sigil, []string{"$var", "@attr"}},
+		{"var", sigil, nil},
+	}
+
+	for _, test := range tests {
+		sc := NewWithOptions(strings.NewReader(test.in), Options{Ident: test.rules})
+		var out []string
+		for sc.IsIdent() {
+			out = append(out, sc.Ident())
+		}
+		if strings.Join(out, " ") != strings.Join(test.out, " ") {
+			t.Errorf("input %q produced %q instead of %q", test.in, out, test.out)
+		}
+	}
+}
+
+func TestLispIdentNumbers(t *testing.T) {
+	sc := NewWithOptions(strings.NewReader("(+ -1 .5 - x)"), Options{Ident: LispIdent})
+	sc.Eat("(")
+	var out []string
+	for !sc.Eat(")") {
+		if sc.IsNumber() {
+			out = append(out, "number:"+sc.Number().Text)
+		} else {
+			out = append(out, sc.Ident())
+		}
+	}
+	if want := "+ number:-1 number:.5 - x"; strings.Join(out, " ") != want || sc.Err() != nil {
+		t.Errorf("produced %q instead of %q, error: %v", out, want, sc.Err())
+	}
+}
+
+func TestQualifiedIdent(t *testing.T) {
+	tests := []struct {
+		in      string
+		sep     string
+		noSpace bool
+		out     string
+		err     string
+	}{
+		{"a", ".", false, "a", ""},
+		{"a.b.c", ".", false, "a b c", ""},
+		{"a.b.c x", ".", false, "a b c", ""},
+		{"a . b /* c */ . c", ".", false, "a b c", ""},
+		{"a.\nb", ".", false, "a b", ""},
+		{"pkg::Type::member", "::", false, "pkg Type member", ""},
+		{"a:b", "::", false, "a", ""},
+		{"a.b.c", ".", true, "a b c", ""},
+		{"a.b .c", ".", true, "a b", ""},
+		{"a. b", ".", true, "", "identifier expected after 'a.'"},
+		{"a.\nb", ".", true, "", "identifier expected after 'a.'"},
+		{"a.b.1", ".", false, "", "identifier expected after 'a.b.'"},
+		{"a.b.if", ".", false, "", "identifier expected after 'a.b.', found keyword 'if'"},
+		{"1", ".", false, "", "identifier expected"},
+	}
+
+	for _, test := range tests {
+		opt := DefaultOptions()
+		opt.Keywords = []string{"if"}
+		opt.QualifiedNoSpace = test.noSpace
+		sc := NewWithOptions(strings.NewReader(test.in), opt)
+		out := sc.QualifiedIdent(test.sep)
+		if test.err == "" {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if strings.Join(out, " ") != test.out {
+				t.Errorf("input %q produced %q instead of %q", test.in, out, test.out)
+			}
+		} else if err, ok := sc.Err().(*Error); !ok {
+			t.Errorf("input %q produced error %v", test.in, sc.Err())
+		} else if err.Message != test.err {
+			t.Errorf("input %q produced error %q instead of %q", test.in, err.Message, test.err)
+		}
+	}
+
+	sc := FromString("a.b.\n")
+	sc.QualifiedIdent(".")
+	if err, ok := sc.Err().(*Error); !ok || err.Column != 5 || err.Offset != 5 || err.Position.Line != 1 {
+		t.Errorf("produced error %v", sc.Err())
+	}
+}
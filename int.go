@@ -0,0 +1,196 @@
+package scanner
+
+import (
+	"math/big"
+)
+
+// IsInt8 returns true if the next token is an integer that fits into an int8.
+func (s *Scanner) IsInt8() bool {
+	s.Expect("integer")
+	_, ok := s.PeekInt8()
+	return ok
+}
+
+// PeekInt8 returns the next token as an int8, or (0, false) if the next token is not an integer that fits into an int8.
+// PeekInt8 does not advance the scanner.
+func (s *Scanner) PeekInt8() (int8, bool) {
+	i, _, err := s.peekInt(8)
+	return int8(i), err == nil
+}
+
+// Int8 returns the next token as an int8, or causes an error if the next token is not an integer that fits into an int8.
+func (s *Scanner) Int8() int8 {
+	return int8(s.signed(8))
+}
+
+// IsInt16 returns true if the next token is an integer that fits into an int16.
+func (s *Scanner) IsInt16() bool {
+	s.Expect("integer")
+	_, ok := s.PeekInt16()
+	return ok
+}
+
+// PeekInt16 returns the next token as an int16, or (0, false) if the next token is not an integer that fits into an int16.
+// PeekInt16 does not advance the scanner.
+func (s *Scanner) PeekInt16() (int16, bool) {
+	i, _, err := s.peekInt(16)
+	return int16(i), err == nil
+}
+
+// Int16 returns the next token as an int16, or causes an error if the next token is not an integer that fits into an int16.
+func (s *Scanner) Int16() int16 {
+	return int16(s.signed(16))
+}
+
+// IsInt32 returns true if the next token is an integer that fits into an int32.
+func (s *Scanner) IsInt32() bool {
+	s.Expect("integer")
+	_, ok := s.PeekInt32()
+	return ok
+}
+
+// PeekInt32 returns the next token as an int32, or (0, false) if the next token is not an integer that fits into an int32.
+// PeekInt32 does not advance the scanner.
+func (s *Scanner) PeekInt32() (int32, bool) {
+	i, _, err := s.peekInt(32)
+	return int32(i), err == nil
+}
+
+// Int32 returns the next token as an int32, or causes an error if the next token is not an integer that fits into an int32.
+func (s *Scanner) Int32() int32 {
+	return int32(s.signed(32))
+}
+
+// IsInt64 returns true if the next token is an integer that fits into an int64.
+func (s *Scanner) IsInt64() bool {
+	s.Expect("integer")
+	_, ok := s.PeekInt64()
+	return ok
+}
+
+// PeekInt64 returns the next token as an int64, or (0, false) if the next token is not an integer that fits into an int64.
+// PeekInt64 does not advance the scanner.
+func (s *Scanner) PeekInt64() (int64, bool) {
+	i, _, err := s.peekInt(64)
+	return int64(i), err == nil
+}
+
+// Int64 returns the next token as an int64, or causes an error if the next token is not an integer that fits into an int64.
+func (s *Scanner) Int64() int64 {
+	return int64(s.signed(64))
+}
+
+// IsUint8 returns true if the next token is an integer that fits into a uint8.
+func (s *Scanner) IsUint8() bool {
+	s.Expect("integer")
+	_, ok := s.PeekUint8()
+	return ok
+}
+
+// PeekUint8 returns the next token as a uint8, or (0, false) if the next token is not an integer that fits into a uint8.
+// PeekUint8 does not advance the scanner.
+func (s *Scanner) PeekUint8() (uint8, bool) {
+	i, _, err := s.peekUint(8)
+	return uint8(i), err == nil
+}
+
+// Uint8 returns the next token as a uint8, or causes an error if the next token is not an integer that fits into a uint8.
+func (s *Scanner) Uint8() uint8 {
+	return uint8(s.unsigned(8))
+}
+
+// IsUint16 returns true if the next token is an integer that fits into a uint16.
+func (s *Scanner) IsUint16() bool {
+	s.Expect("integer")
+	_, ok := s.PeekUint16()
+	return ok
+}
+
+// PeekUint16 returns the next token as a uint16, or (0, false) if the next token is not an integer that fits into a uint16.
+// PeekUint16 does not advance the scanner.
+func (s *Scanner) PeekUint16() (uint16, bool) {
+	i, _, err := s.peekUint(16)
+	return uint16(i), err == nil
+}
+
+// Uint16 returns the next token as a uint16, or causes an error if the next token is not an integer that fits into a uint16.
+func (s *Scanner) Uint16() uint16 {
+	return uint16(s.unsigned(16))
+}
+
+// IsUint32 returns true if the next token is an integer that fits into a uint32.
+func (s *Scanner) IsUint32() bool {
+	s.Expect("integer")
+	_, ok := s.PeekUint32()
+	return ok
+}
+
+// PeekUint32 returns the next token as a uint32, or (0, false) if the next token is not an integer that fits into a uint32.
+// PeekUint32 does not advance the scanner.
+func (s *Scanner) PeekUint32() (uint32, bool) {
+	i, _, err := s.peekUint(32)
+	return uint32(i), err == nil
+}
+
+// Uint32 returns the next token as a uint32, or causes an error if the next token is not an integer that fits into a uint32.
+func (s *Scanner) Uint32() uint32 {
+	return uint32(s.unsigned(32))
+}
+
+// IsUint64 returns true if the next token is an integer that fits into a uint64.
+func (s *Scanner) IsUint64() bool {
+	s.Expect("integer")
+	_, ok := s.PeekUint64()
+	return ok
+}
+
+// PeekUint64 returns the next token as a uint64, or (0, false) if the next token is not an integer that fits into a uint64.
+// PeekUint64 does not advance the scanner.
+func (s *Scanner) PeekUint64() (uint64, bool) {
+	i, _, err := s.peekUint(64)
+	return uint64(i), err == nil
+}
+
+// Uint64 returns the next token as a uint64, or causes an error if the next token is not an integer that fits into a uint64.
+func (s *Scanner) Uint64() uint64 {
+	return uint64(s.unsigned(64))
+}
+
+// IsBigInt returns true if the next token is an integer.
+func (s *Scanner) IsBigInt() bool {
+	s.Expect("integer")
+	_, ok := s.PeekBigInt()
+	return ok
+}
+
+// PeekBigInt returns the next token as a big.Int, or (nil, false) if the next token is not an integer.
+// PeekBigInt does not advance the scanner.
+func (s *Scanner) PeekBigInt() (*big.Int, bool) {
+	i, _ := s.peekBigInt()
+	return i, i != nil
+}
+
+// BigInt returns the next token as a big.Int, or causes an error if the next token is not an integer.
+// Unlike the other integer methods, BigInt accepts integers of any size.
+func (s *Scanner) BigInt() *big.Int {
+	i, n := s.peekBigInt()
+	if i == nil {
+		s.Fail("integer expected")
+		return nil
+	}
+	s.pos += n
+	s.space()
+	return i
+}
+
+func (s *Scanner) peekBigInt() (*big.Int, int) {
+	text, kind, _ := s.peekNumber()
+	if text == "" || kind != IntNumber {
+		return nil, 0
+	}
+	i, ok := new(big.Int).SetString(text, 0)
+	if !ok {
+		return nil, 0
+	}
+	return i, len(text)
+}
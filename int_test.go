@@ -0,0 +1,111 @@
+package scanner_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	. "github.com/jfreymuth/scanner"
+)
+
+func TestSizedInt(t *testing.T) {
+	tests := []struct {
+		in   string
+		size string
+		out  string
+		err  string
+	}{
+		{"127", "int8", "127", ""},
+		{"-128", "int8", "-128", ""},
+		{"128", "int8", "", "integer overflow"},
+		{"-129", "int8", "", "integer overflow"},
+		{"0x7fff", "int16", "32767", ""},
+		{"0x8000", "int16", "", "integer overflow"},
+		{"-2147483648", "int32", "-2147483648", ""},
+		{"2147483648", "int32", "", "integer overflow"},
+		{"9223372036854775807", "int64", "9223372036854775807", ""},
+		{"-9223372036854775808", "int64", "-9223372036854775808", ""},
+		{"9223372036854775808", "int64", "", "integer overflow"},
+		{"255", "uint8", "255", ""},
+		{"256", "uint8", "", "integer overflow"},
+		{"-1", "uint8", "", "integer expected"},
+		{"0xffff", "uint16", "65535", ""},
+		{"4294967295", "uint32", "4294967295", ""},
+		{"4294967296", "uint32", "", "integer overflow"},
+		{"18446744073709551615", "uint64", "18446744073709551615", ""},
+		{"18446744073709551616", "uint64", "", "integer overflow"},
+		{"0xffffffffffffffff", "uint64", "18446744073709551615", ""},
+		{"1.5", "int64", "", "integer expected"},
+		{"a", "uint64", "", "integer expected"},
+		{"99999999999999999999", "int", "", "integer overflow"},
+		{"123456789012345678901234567890", "big", "123456789012345678901234567890", ""},
+		{"-0x1000000000000000000000000", "big", "-79228162514264337593543950336", ""},
+		{"017", "big", "15", ""},
+		{"1.5", "big", "", "integer expected"},
+		{"09", "big", "", "integer expected"},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		var out interface{}
+		var ok bool
+		switch test.size {
+		case "int":
+			ok, out = sc.IsInt(), sc.Int()
+		case "int8":
+			ok, out = sc.IsInt8(), sc.Int8()
+		case "int16":
+			ok, out = sc.IsInt16(), sc.Int16()
+		case "int32":
+			ok, out = sc.IsInt32(), sc.Int32()
+		case "int64":
+			ok, out = sc.IsInt64(), sc.Int64()
+		case "uint8":
+			ok, out = sc.IsUint8(), sc.Uint8()
+		case "uint16":
+			ok, out = sc.IsUint16(), sc.Uint16()
+		case "uint32":
+			ok, out = sc.IsUint32(), sc.Uint32()
+		case "uint64":
+			ok, out = sc.IsUint64(), sc.Uint64()
+		case "big":
+			ok = sc.IsBigInt()
+			if i := sc.BigInt(); i != nil {
+				out = i
+			}
+		}
+		if ok != (test.err == "") {
+			t.Errorf("input %q: Is returned %v for %s", test.in, ok, test.size)
+		}
+		if test.err == "" {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if fmt.Sprint(out) != test.out {
+				t.Errorf("input %q produced output %v instead of %s", test.in, out, test.out)
+			}
+		} else if err, ok := sc.Err().(*Error); !ok {
+			t.Errorf("input %q produced error %v", test.in, sc.Err())
+		} else if err.Message != test.err {
+			t.Errorf("input %q produced error %q instead of %q", test.in, err.Message, test.err)
+		}
+	}
+}
+
+func TestPeekSizedInt(t *testing.T) {
+	sc := FromString("300 x")
+	if _, ok := sc.PeekInt8(); ok {
+		t.Error("PeekInt8 accepted 300")
+	}
+	if i, ok := sc.PeekInt16(); !ok || i != 300 {
+		t.Errorf("PeekInt16 returned %d, %v", i, ok)
+	}
+	if u, ok := sc.PeekUint64(); !ok || u != 300 {
+		t.Errorf("PeekUint64 returned %d, %v", u, ok)
+	}
+	if i, ok := sc.PeekBigInt(); !ok || i.Cmp(big.NewInt(300)) != 0 {
+		t.Errorf("PeekBigInt returned %v, %v", i, ok)
+	}
+	if sc.Int() != 300 || sc.Ident() != "x" || sc.Err() != nil {
+		t.Errorf("Peek methods advanced the scanner")
+	}
+}
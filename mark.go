@@ -0,0 +1,64 @@
+package scanner
+
+// A Mark is a checkpoint in a scanner's input, created by Scanner.Mark.
+type Mark struct {
+	line    string
+	pos, ln int
+	off     int
+	skip    int
+	r       rune
+	size    int
+	err     error
+	nerrs   int
+
+	syncing  bool
+	syncLine string
+	syncPos  int
+}
+
+// Mark returns a checkpoint that can later be passed to Reset.
+// While a mark is live, the scanner keeps every line it reads, so marks should be released
+// with Release once they are no longer needed.
+func (s *Scanner) Mark() Mark {
+	if s.marks == 0 {
+		s.trim()
+	}
+	s.marks++
+	return Mark{s.line, s.pos, s.ln, s.off, s.skip, s.r, s.size, s.err, len(s.errs), s.syncing, s.syncLine, s.syncPos}
+}
+
+// Reset rewinds the scanner to a checkpoint returned by Mark.
+// The mark stays live, so Reset may be called several times with the same mark.
+// Resetting to a mark that has already been released is not allowed.
+// Errors reported with Fail are discarded, but errors from reading the input are kept.
+func (s *Scanner) Reset(m Mark) {
+	s.line, s.pos, s.ln, s.off, s.skip = m.line, m.pos, m.ln, m.off, m.skip
+	s.r, s.size = m.r, m.size
+	s.err = m.err
+	if s.srcErr != nil {
+		s.err = s.srcErr
+	}
+	s.errs = s.errs[:m.nerrs]
+	s.syncing, s.syncLine, s.syncPos = m.syncing, m.syncLine, m.syncPos
+}
+
+// Release discards a checkpoint returned by Mark.
+// Once all marks are released, lines that have already been consumed are no longer kept.
+func (s *Scanner) Release(m Mark) {
+	if s.marks > 0 {
+		s.marks--
+	}
+	if s.marks == 0 {
+		s.trim()
+	}
+}
+
+// trim drops all buffered lines up to and including the current one.
+func (s *Scanner) trim() {
+	if n := s.ln - s.bufLn; n >= len(s.buf) {
+		s.buf = nil
+	} else if n > 0 {
+		s.buf = s.buf[n:]
+	}
+	s.bufLn = s.ln
+}
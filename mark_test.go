@@ -0,0 +1,110 @@
+package scanner_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/jfreymuth/scanner"
+)
+
+func TestMark(t *testing.T) {
+	tests := []struct {
+		in    string
+		first []string
+		out   []string
+	}{
+		{"a b c", []string{"a", "b"}, []string{"a", "b", "c"}},
+		{"a\nb\nc", []string{"a", "b", "c"}, []string{"a", "b", "c"}},
+		{"a /* \n */ b // c\n c", []string{"a", "b"}, []string{"a", "b", "c"}},
+		{"a\n\n\nb\nc d", []string{"a", "b", "c", "d"}, []string{"a", "b", "c", "d"}},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		m := sc.Mark()
+		for _, d := range test.first {
+			sc.Demand(d)
+		}
+		sc.Reset(m)
+		sc.Release(m)
+		for i, o := range test.out {
+			if out := sc.Ident(); out != o {
+				t.Errorf("input %q produced %d. output %q instead of %q", test.in, i, out, o)
+			}
+		}
+		if sc.Err() != nil {
+			t.Errorf("input %q produced error: %s", test.in, sc.Err())
+		}
+		if !sc.End() {
+			t.Errorf("input %q: End returned false", test.in)
+		}
+	}
+}
+
+func TestMarkError(t *testing.T) {
+	sc := FromString("a\nb\nc")
+	sc.Demand("a")
+	m := sc.Mark()
+	sc.Demand("b")
+	sc.Demand("x")
+	if sc.Err() == nil {
+		t.Fatal("Demand(\"x\") should produce an error")
+	}
+	sc.Reset(m)
+	if sc.Err() != nil {
+		t.Errorf("Reset did not clear error: %s", sc.Err())
+	}
+	sc.Demand("b")
+	sc.Reset(m)
+	sc.Demand("b")
+	sc.Release(m)
+	sc.Demand("c")
+	if sc.Err() != nil {
+		t.Errorf("produced error: %s", sc.Err())
+	}
+	if !sc.End() {
+		t.Error("End returned false")
+	}
+}
+
+func TestMarkSourceError(t *testing.T) {
+	sc := NewWithOptions(strings.NewReader("a b\ncdefgh"), Options{MaxLineLength: 5})
+	sc.Demand("a")
+	m := sc.Mark()
+	sc.Demand("b")
+	sc.Reset(m)
+	err, ok := sc.Err().(*Error)
+	if !ok || err.Message != "line too long" {
+		t.Fatalf("Reset discarded the error from the input: %v", sc.Err())
+	}
+	if p := (Position{"", 9, 2, 6}); err.Position != p || err.Line != "cdefg" {
+		t.Errorf("produced error at %v in %q instead of %v in \"cdefg\"", err.Position, err.Line, p)
+	}
+	if !sc.End() {
+		t.Error("End returned false")
+	}
+}
+
+func TestMarkNested(t *testing.T) {
+	sc := FromString("a\nb\nc\nd")
+	outer := sc.Mark()
+	sc.Demand("a")
+	inner := sc.Mark()
+	sc.Demand("b")
+	sc.Demand("c")
+	sc.Reset(inner)
+	sc.Release(inner)
+	sc.Demand("b")
+	sc.Demand("c")
+	sc.Reset(outer)
+	sc.Release(outer)
+	for _, d := range []string{"a", "b", "c", "d"} {
+		sc.Demand(d)
+	}
+	if sc.Err() != nil {
+		t.Errorf("produced error: %s", sc.Err())
+	}
+	if !sc.End() {
+		t.Error("End returned false")
+	}
+}
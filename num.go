@@ -1,12 +1,35 @@
 package scanner
 
 import (
+	"math/big"
 	"strconv"
+	"strings"
 	"unicode"
+	"unicode/utf8"
+)
+
+// NumberSyntax is a set of optional features of number literals.
+type NumberSyntax uint
+
+// Optional features of number literals.
+// A leading '-' is always accepted, but only if it is directly followed by the number,
+// so that in an expression like "a - 1" the minus can be parsed as an operator.
+const (
+	BinaryLiterals    NumberSyntax = 1 << iota // 0b1010
+	OctalLiterals                              // 0o17
+	DigitSeparators                            // 1_000_000
+	HexFloats                                  // 0x1p-2
+	ImaginaryLiterals                          // 2.5i, only accepted by Complex
+	PlusSign                                   // +1, not part of go number literals
+	InfNaN                                     // inf, -Infinity and NaN in any case, not part of go number literals
+
+	// GoNumbers enables all features of go number literals.
+	GoNumbers = BinaryLiterals | OctalLiterals | DigitSeparators | HexFloats | ImaginaryLiterals
 )
 
 // IsInt returns true if the next token is an int.
 func (s *Scanner) IsInt() bool {
+	s.Expect("integer")
 	_, ok := s.PeekInt()
 	return ok
 }
@@ -14,24 +37,18 @@ func (s *Scanner) IsInt() bool {
 // PeekInt returns the next token as an int, or (0, false) if the next token is not an int.
 // PeekInt does not advance the scanner.
 func (s *Scanner) PeekInt() (int, bool) {
-	i, n := peekInt(s.line[s.pos:])
-	return i, n > 0
+	i, _, err := s.peekInt(strconv.IntSize)
+	return int(i), err == nil
 }
 
 // Int returns the next token as an int, or causes an error if the next token is not an int.
 func (s *Scanner) Int() int {
-	i, n := peekInt(s.line[s.pos:])
-	if n == 0 {
-		s.Fail("integer expected")
-		return 0
-	}
-	s.pos += n
-	s.space()
-	return i
+	return int(s.signed(strconv.IntSize))
 }
 
 // IsFloat returns true if the next token is a float.
 func (s *Scanner) IsFloat() bool {
+	s.Expect("float")
 	_, ok := s.PeekFloat()
 	return ok
 }
@@ -39,68 +56,415 @@ func (s *Scanner) IsFloat() bool {
 // PeekFloat returns the next token as a float, or (0, false) if the next token is not a float.
 // PeekFloat does not advance the scanner.
 func (s *Scanner) PeekFloat() (float64, bool) {
-	i, n := peekInt(s.line[s.pos:])
-	if n != 0 {
-		return float64(i), true
-	}
-	f, n := peekFloat(s.line[s.pos:])
-	if n != 0 {
-		return f, true
-	}
-	return 0, false
+	f, _, err := s.peekFloat()
+	return f, err == nil
 }
 
 // Float returns the next token as a float, or causes an error if the next token is not a float.
 func (s *Scanner) Float() float64 {
-	i, n := peekInt(s.line[s.pos:])
-	if n != 0 {
-		s.pos += n
-		s.space()
-		return float64(i)
-	}
-	f, n := peekFloat(s.line[s.pos:])
-	if n != 0 {
-		s.pos += n
-		s.space()
-		return f
-	}
-	s.Fail("float expected")
-	return 0
+	f, n, err := s.peekFloat()
+	if err != nil {
+		if err == strconv.ErrRange {
+			s.Fail("float overflow")
+		} else {
+			s.Fail("float expected")
+		}
+		return 0
+	}
+	s.pos += n
+	s.space()
+	return f
 }
 
-func peekInt(line string) (i, n int) {
-	l := len(line)
-	for i, r := range line {
-		if (i != 0 || r != '-') && r != '.' && !unicode.IsDigit(r) && !unicode.IsLetter(r) {
-			l = i
-			break
+// IsComplex returns true if the next token is a float or an imaginary literal.
+func (s *Scanner) IsComplex() bool {
+	s.Expect("number")
+	_, ok := s.PeekComplex()
+	return ok
+}
+
+// PeekComplex returns the next token as a complex number, or (0, false) if the next token is not a number.
+// PeekComplex does not advance the scanner.
+func (s *Scanner) PeekComplex() (complex128, bool) {
+	c, _, err := s.peekComplex()
+	return c, err == nil
+}
+
+// Complex returns the next token as a complex number, or causes an error if the next token is not a number.
+// Imaginary literals like 2.5i are only accepted if the ImaginaryLiterals option is enabled.
+// Other numbers are returned as a complex number with an imaginary part of 0.
+func (s *Scanner) Complex() complex128 {
+	c, n, err := s.peekComplex()
+	if err != nil {
+		if err == strconv.ErrRange {
+			s.Fail("float overflow")
+		} else {
+			s.Fail("number expected")
 		}
+		return 0
 	}
+	s.pos += n
+	s.space()
+	return c
+}
 
-	i64, err := strconv.ParseInt(line[:l], 0, strconv.IntSize)
+// IsRat returns true if the next token is a rational number.
+func (s *Scanner) IsRat() bool {
+	s.Expect("rational")
+	_, ok := s.PeekRat()
+	return ok
+}
+
+// PeekRat returns the next token as a rational number, or (nil, false) if the next token is not a rational number.
+// PeekRat does not advance the scanner.
+func (s *Scanner) PeekRat() (*big.Rat, bool) {
+	r, _, msg := s.peekRat()
+	return r, msg == ""
+}
+
+// Rat returns the next token as a rational number, or causes an error if the next token is not a rational number.
+// A rational number is a number, optionally followed by '/' and an unsigned denominator, like 3/4 or 1.5/2,
+// without any whitespace in between. The result is exact.
+func (s *Scanner) Rat() *big.Rat {
+	r, n, msg := s.peekRat()
+	if msg != "" {
+		s.Fail(msg)
+		return nil
+	}
+	s.pos += n
+	s.space()
+	return r
+}
+
+// IsPercent returns true if the next token is a percentage.
+func (s *Scanner) IsPercent() bool {
+	s.Expect("percentage")
+	_, ok := s.PeekPercent()
+	return ok
+}
+
+// PeekPercent returns the next token as a fraction, or (0, false) if the next token is not a percentage.
+// PeekPercent does not advance the scanner.
+func (s *Scanner) PeekPercent() (float64, bool) {
+	f, _, err := s.peekPercent()
+	return f, err == nil
+}
+
+// Percent returns the next token as a fraction, or causes an error if the next token is not a percentage.
+// A percentage is a number directly followed by '%', 15% is returned as 0.15.
+func (s *Scanner) Percent() float64 {
+	f, n, err := s.peekPercent()
 	if err != nil {
-		return 0, 0
+		if err == strconv.ErrRange {
+			s.Fail("float overflow")
+		} else {
+			s.Fail("percentage expected")
+		}
+		return 0
 	}
-	return int(i64), l
+	s.pos += n
+	s.space()
+	return f
 }
 
-func peekFloat(line string) (f float64, n int) {
-	l := len(line)
-	e := false
-	for i, r := range line {
-		if !(i == 0 && r == '-') && !(e && (r == '-' || r == '+')) && r != '.' && !unicode.IsDigit(r) && !unicode.IsLetter(r) {
-			l = i
-			break
+// signed consumes the next token as an integer with the given bit size,
+// or causes an error if the next token is not an integer or does not fit.
+func (s *Scanner) signed(bits int) int64 {
+	i, n, err := s.peekInt(bits)
+	if err != nil {
+		s.failInt(err)
+		return 0
+	}
+	s.pos += n
+	s.space()
+	return i
+}
+
+// unsigned consumes the next token as an unsigned integer with the given bit size,
+// or causes an error if the next token is not an unsigned integer or does not fit.
+func (s *Scanner) unsigned(bits int) uint64 {
+	u, n, err := s.peekUint(bits)
+	if err != nil {
+		s.failInt(err)
+		return 0
+	}
+	s.pos += n
+	s.space()
+	return u
+}
+
+func (s *Scanner) failInt(err error) {
+	if err == strconv.ErrRange {
+		s.Fail("integer overflow")
+	} else {
+		s.Fail("integer expected")
+	}
+}
+
+// peekInt parses the integer at the start of the scanner's input.
+// It returns the length of the integer and either nil, strconv.ErrSyntax or strconv.ErrRange.
+func (s *Scanner) peekInt(bits int) (int64, int, error) {
+	text, kind, _ := s.peekNumber()
+	if text == "" || kind != IntNumber {
+		return 0, 0, strconv.ErrSyntax
+	}
+	i, err := strconv.ParseInt(text, 0, bits)
+	if err != nil {
+		return 0, 0, err.(*strconv.NumError).Err
+	}
+	return i, len(text), nil
+}
+
+// peekUint is like peekInt, but parses an unsigned integer.
+func (s *Scanner) peekUint(bits int) (uint64, int, error) {
+	text, kind, _ := s.peekNumber()
+	if text == "" || kind != IntNumber {
+		return 0, 0, strconv.ErrSyntax
+	}
+	u, err := strconv.ParseUint(strings.TrimPrefix(text, "+"), 0, bits)
+	if err != nil {
+		return 0, 0, err.(*strconv.NumError).Err
+	}
+	return u, len(text), nil
+}
+
+// peekFloat parses the integer or float at the start of the scanner's input.
+// It returns the length of the number and either nil, strconv.ErrSyntax or strconv.ErrRange.
+func (s *Scanner) peekFloat() (float64, int, error) {
+	text, kind, _ := s.peekNumber()
+	if text == "" || kind == ImaginaryNumber {
+		return 0, 0, strconv.ErrSyntax
+	}
+	f, err := parseFloat(text, kind)
+	if err != nil {
+		return 0, 0, err
+	}
+	return f, len(text), nil
+}
+
+// peekComplex parses the number or imaginary literal at the start of the scanner's input.
+// It returns the length of the number and either nil, strconv.ErrSyntax or strconv.ErrRange.
+func (s *Scanner) peekComplex() (complex128, int, error) {
+	text, kind, base := s.peekNumber()
+	if text == "" {
+		return 0, 0, strconv.ErrSyntax
+	}
+	if kind == ImaginaryNumber {
+		f, err := parseFloat(NumberLiteral{text, kind, base}.mantissa())
+		if err != nil {
+			return 0, 0, err
+		}
+		return complex(0, f), len(text), nil
+	}
+	f, err := parseFloat(text, kind)
+	if err != nil {
+		return 0, 0, err
+	}
+	return complex(f, 0), len(text), nil
+}
+
+// peekRat parses the rational number at the start of the scanner's input.
+// It returns the length of the number and an error message, or an empty string.
+func (s *Scanner) peekRat() (*big.Rat, int, string) {
+	line := s.line[s.pos:]
+	syn := s.opt.Numbers &^ (ImaginaryLiterals | InfNaN)
+	n, kind, base := scanNumber(line, syn)
+	if n == 0 {
+		return nil, 0, "rational expected"
+	}
+	r, err := NumberLiteral{line[:n], kind, base}.Rat()
+	if err != nil {
+		return nil, 0, "rational overflow"
+	}
+	if n+1 < len(line) && line[n] == '/' && (line[n+1] >= '0' && line[n+1] <= '9' || line[n+1] == '.') {
+		d := line[n+1:]
+		m, kind, base := scanNumber(d, syn)
+		if m == 0 {
+			return nil, 0, "rational expected"
 		}
-		e = false
-		if r == 'e' || r == 'E' {
-			e = true
+		q, err := NumberLiteral{d[:m], kind, base}.Rat()
+		if err != nil {
+			return nil, 0, "rational overflow"
 		}
+		if q.Sign() == 0 {
+			return nil, 0, "division by zero"
+		}
+		r.Quo(r, q)
+		n += 1 + m
+		if strings.HasPrefix(line[n:], "/") {
+			return nil, 0, "rational expected"
+		}
+	}
+	if !isNumberEnd(line[n:]) {
+		return nil, 0, "rational expected"
+	}
+	return r, n, ""
+}
+
+// peekPercent parses the percentage at the start of the scanner's input.
+// It returns the length of the percentage and either nil, strconv.ErrSyntax or strconv.ErrRange.
+func (s *Scanner) peekPercent() (float64, int, error) {
+	line := s.line[s.pos:]
+	n, kind, _ := scanNumber(line, s.opt.Numbers&^(ImaginaryLiterals|InfNaN))
+	if n == 0 || n >= len(line) || line[n] != '%' {
+		return 0, 0, strconv.ErrSyntax
 	}
+	f, err := parseFloat(line[:n], kind)
+	if err != nil {
+		return 0, 0, err
+	}
+	return f / 100, n + 1, nil
+}
 
-	f, err := strconv.ParseFloat(line[:l], 64)
+// parseFloat converts an integer or float literal to a float64.
+func parseFloat(text string, kind NumberKind) (float64, error) {
+	if kind == IntNumber {
+		// strconv.ParseFloat does not accept all integer literals, like 0b101 or 017
+		i, ok := new(big.Int).SetString(text, 0)
+		if !ok {
+			return 0, strconv.ErrSyntax
+		}
+		f, _ := new(big.Float).SetInt(i).Float64()
+		return f, nil
+	}
+	f, err := strconv.ParseFloat(text, 64)
 	if err != nil {
-		return 0, 0
+		return 0, err.(*strconv.NumError).Err
+	}
+	return f, nil
+}
+
+// peekNumber returns the number literal at the start of the scanner's input, its kind and its base.
+// If the input does not start with a valid number, peekNumber returns an empty string.
+func (s *Scanner) peekNumber() (string, NumberKind, int) {
+	line := s.line[s.pos:]
+	n, kind, base := scanNumber(line, s.opt.Numbers)
+	if n == 0 {
+		return "", 0, 0
+	}
+	if !isNumberEnd(line[n:]) {
+		return "", 0, 0
+	}
+	return line[:n], kind, base
+}
+
+// isNumberEnd returns true if a number may be followed by rest.
+func isNumberEnd(rest string) bool {
+	r, _ := utf8.DecodeRuneInString(rest)
+	return !(r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// scanNumber returns the length, kind and base of the number literal at the start of line,
+// or 0 if line does not start with a valid number.
+func scanNumber(line string, syn NumberSyntax) (int, NumberKind, int) {
+	i := 0
+	if i < len(line) && (line[i] == '-' || line[i] == '+' && syn&PlusSign != 0) {
+		i++
+	}
+	if syn&InfNaN != 0 {
+		if n := scanInfNaN(line[i:], i > 0); n > 0 {
+			return i + n, FloatNumber, 10
+		}
+	}
+	start := i
+	base := 10
+	if i+1 < len(line) && line[i] == '0' {
+		switch line[i+1] | 0x20 {
+		case 'x':
+			base = 16
+		case 'b':
+			if syn&BinaryLiterals != 0 {
+				base = 2
+			}
+		case 'o':
+			if syn&OctalLiterals != 0 {
+				base = 8
+			}
+		}
+		if base != 10 {
+			i += 2
+		}
+	}
+	kind := IntNumber
+	i, digits := scanDigits(line, i, base, syn, base != 10)
+	if i < len(line) && line[i] == '.' && (base == 10 || base == 16 && syn&HexFloats != 0) {
+		kind = FloatNumber
+		var frac bool
+		i, frac = scanDigits(line, i+1, base, syn, false)
+		digits = digits || frac
+	}
+	if !digits {
+		return 0, 0, 0
+	}
+	exp := false
+	if i < len(line) {
+		if e := line[i] | 0x20; base == 10 && e == 'e' || base == 16 && e == 'p' && syn&HexFloats != 0 {
+			// an exponent without digits is not part of the number, for example in "1em"
+			j := i + 1
+			if j < len(line) && (line[j] == '+' || line[j] == '-') {
+				j++
+			}
+			if j, digits = scanDigits(line, j, 10, syn, false); digits {
+				kind = FloatNumber
+				i = j
+				exp = true
+			}
+		}
+	}
+	if base == 16 && kind == FloatNumber && !exp {
+		// as in go, a hexadecimal mantissa with a point requires an exponent
+		return 0, 0, 0
+	}
+	if i < len(line) && line[i] == 'i' && syn&ImaginaryLiterals != 0 {
+		kind = ImaginaryNumber
+		i++
+	}
+	if kind == IntNumber && base == 10 && line[start] == '0' && i-start > 1 {
+		// legacy octal literal like 017
+		if strings.ContainsAny(line[start:i], "89") {
+			return 0, 0, 0
+		}
+		base = 8
+	}
+	return i, kind, base
+}
+
+// scanInfNaN returns the length of the infinity or NaN at the start of line, or 0.
+// NaN can not have a sign.
+func scanInfNaN(line string, signed bool) int {
+	for _, w := range [...]string{"infinity", "inf", "nan"} {
+		if w == "nan" && signed {
+			break
+		}
+		if len(line) >= len(w) && strings.EqualFold(line[:len(w)], w) {
+			return len(w)
+		}
+	}
+	return 0
+}
+
+// scanDigits skips the digits of the given base starting at line[i].
+// As in go, a digit separator must be between two digits, or between a base prefix and a digit if prefix is true.
+// It returns the index after the digits and whether there were any digits.
+func scanDigits(line string, i, base int, syn NumberSyntax, prefix bool) (int, bool) {
+	digits := false
+	for ; i < len(line); i++ {
+		c := line[i]
+		if c == '_' && syn&DigitSeparators != 0 && (digits || prefix) && i+1 < len(line) && isDigit(line[i+1], base) {
+			continue
+		}
+		if !isDigit(c, base) {
+			return i, digits
+		}
+		digits = true
+	}
+	return i, digits
+}
+
+// isDigit returns true if c is a digit of the given base.
+func isDigit(c byte, base int) bool {
+	if base == 16 && c|0x20 >= 'a' && c|0x20 <= 'f' {
+		return true
 	}
-	return f, l
+	return c >= '0' && c <= '9' && (base >= 10 || int(c-'0') < base)
 }
@@ -1,6 +1,8 @@
 package scanner_test
 
 import (
+	"math"
+	"strings"
 	"testing"
 
 	. "github.com/jfreymuth/scanner"
@@ -143,3 +145,252 @@ func TestFloat(t *testing.T) {
 		}
 	}
 }
+
+func TestModernNumbers(t *testing.T) {
+	tests := []struct {
+		in      string
+		disable NumberSyntax
+		method  string
+		out     complex128
+		valid   bool
+	}{
+		{"0b101", 0, "int", 5, true},
+		{"0B11", 0, "int", 3, true},
+		{"0b102", 0, "int", 0, false},
+		{"0b101", BinaryLiterals, "int", 0, false},
+		{"0o17", 0, "int", 15, true},
+		{"-0O17", 0, "int", -15, true},
+		{"0o8", 0, "int", 0, false},
+		{"0o17", OctalLiterals, "int", 0, false},
+		{"017", OctalLiterals, "int", 15, true},
+		{"1_000_000", 0, "int", 1000000, true},
+		{"0x_ff", 0, "int", 255, true},
+		{"0b1_0", 0, "int", 2, true},
+		{"1__0", 0, "int", 0, false},
+		{"1_", 0, "int", 0, false},
+		{"_1", 0, "int", 0, false},
+		{"0_7", 0, "int", 7, true},
+		{"0b_1", 0, "int", 1, true},
+		{"0x_", 0, "int", 0, false},
+		{"0x__1", 0, "int", 0, false},
+		{"0b1_2", 0, "int", 0, false},
+		{"1_.5", 0, "float", 0, false},
+		{"1._5", 0, "float", 0, false},
+		{"1e_5", 0, "float", 0, false},
+		{"1e1_0", 0, "float", 1e10, true},
+		{"1_000", DigitSeparators, "int", 0, false},
+		{"1_000.5", 0, "float", 1000.5, true},
+		{"1_000.5", DigitSeparators, "float", 0, false},
+		{"0b101", 0, "float", 5, true},
+		{"0o17", 0, "float", 15, true},
+		{"0x1p-2", 0, "float", 0.25, true},
+		{"0x1.8p1", 0, "float", 3, true},
+		{"0X.8P0", 0, "float", 0.5, true},
+		{"0x1.8", 0, "float", 0, false},
+		{"0x1p-2", HexFloats, "float", 0, false},
+		{"0x1p-2", 0, "int", 0, false},
+		{"2i", 0, "int", 0, false},
+		{"2i", 0, "float", 0, false},
+		{"2i", 0, "complex", 2i, true},
+		{"1.5i", 0, "complex", 1.5i, true},
+		{"-1e3i", 0, "complex", -1e3i, true},
+		{"0x10i", 0, "complex", 16i, true},
+		{"0b11i", 0, "complex", 3i, true},
+		{"0123i", 0, "complex", 123i, true},
+		{"0x1p2i", 0, "complex", 4i, true},
+		{"2i", ImaginaryLiterals, "complex", 0, false},
+		{"2.5", 0, "complex", 2.5, true},
+		{"12", 0, "complex", 12, true},
+		{"1e400", 0, "float", 0, false},
+		{"a", 0, "complex", 0, false},
+	}
+
+	for _, test := range tests {
+		opt := DefaultOptions()
+		opt.Numbers &^= test.disable
+		sc := NewWithOptions(strings.NewReader(test.in), opt)
+		var ok bool
+		var out complex128
+		switch test.method {
+		case "int":
+			ok, out = sc.IsInt(), complex(float64(sc.Int()), 0)
+		case "float":
+			ok, out = sc.IsFloat(), complex(sc.Float(), 0)
+		case "complex":
+			ok, out = sc.IsComplex(), sc.Complex()
+		}
+		if ok != test.valid {
+			t.Errorf("input %q: Is returned %v for %s", test.in, ok, test.method)
+		}
+		if test.valid {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if out != test.out {
+				t.Errorf("input %q produced output %v instead of %v", test.in, out, test.out)
+			}
+		} else if sc.Err() == nil {
+			t.Errorf("input %q should produce an error", test.in)
+		}
+	}
+
+	sc := FromString("1e400")
+	sc.Float()
+	if msg := sc.Err().(*Error).Message; msg != "float overflow" {
+		t.Errorf("produced error %q instead of \"float overflow\"", msg)
+	}
+}
+
+func TestSignsAndInfNaN(t *testing.T) {
+	tests := []struct {
+		in     string
+		enable NumberSyntax
+		method string
+		out    float64
+		valid  bool
+	}{
+		{"+1", 0, "int", 0, false},
+		{"+1", PlusSign, "int", 1, true},
+		{"+1", PlusSign, "uint", 1, true},
+		{"-1", PlusSign, "uint", 0, false},
+		{"+1.5", PlusSign, "float", 1.5, true},
+		{"+-1", PlusSign, "int", 0, false},
+		{"- 1", PlusSign, "int", 0, false},
+		{"+ 1", PlusSign, "float", 0, false},
+		{"inf", 0, "float", 0, false},
+		{"inf", InfNaN, "float", math.Inf(1), true},
+		{"-Inf", InfNaN, "float", math.Inf(-1), true},
+		{"INFINITY", InfNaN, "float", math.Inf(1), true},
+		{"+inf", InfNaN, "float", 0, false},
+		{"+inf", InfNaN | PlusSign, "float", math.Inf(1), true},
+		{"NaN", InfNaN, "float", math.NaN(), true},
+		{"nan", InfNaN, "float", math.NaN(), true},
+		{"-nan", InfNaN, "float", 0, false},
+		{"info", InfNaN, "float", 0, false},
+		{"inf", InfNaN, "int", 0, false},
+		{"inf", InfNaN, "complex", math.Inf(1), true},
+	}
+
+	for _, test := range tests {
+		opt := DefaultOptions()
+		opt.Numbers |= test.enable
+		sc := NewWithOptions(strings.NewReader(test.in), opt)
+		var ok bool
+		var out float64
+		switch test.method {
+		case "int":
+			ok, out = sc.IsInt(), float64(sc.Int())
+		case "uint":
+			ok, out = sc.IsUint64(), float64(sc.Uint64())
+		case "float":
+			ok, out = sc.IsFloat(), sc.Float()
+		case "complex":
+			ok = sc.IsComplex()
+			out = real(sc.Complex())
+		}
+		if ok != test.valid {
+			t.Errorf("input %q: Is returned %v for %s", test.in, ok, test.method)
+		}
+		if test.valid {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if out != test.out && !(math.IsNaN(out) && math.IsNaN(test.out)) {
+				t.Errorf("input %q produced output %v instead of %v", test.in, out, test.out)
+			}
+		} else if sc.Err() == nil {
+			t.Errorf("input %q should produce an error", test.in)
+		}
+	}
+
+	tok := NewTokenizer(FromString("a - 1"))
+	for _, kind := range []TokenKind{IdentToken, PunctToken, IntToken, EOFToken} {
+		if tk := tok.Next(); tk.Kind != kind {
+			t.Errorf("produced token %v %q instead of %v", tk.Kind, tk.Text, kind)
+		}
+	}
+}
+
+func TestRat(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+		err string
+	}{
+		{"3/4", "3/4", ""},
+		{"6/8", "3/4", ""},
+		{"-3/4", "-3/4", ""},
+		{"5", "5/1", ""},
+		{"0.1", "1/10", ""},
+		{"1.5/2", "3/4", ""},
+		{"1/.5", "2/1", ""},
+		{"0x10/3", "16/3", ""},
+		{"3/4 x", "3/4", ""},
+		{"3 / 4", "3/1", ""},
+		{"3/-4", "3/1", ""},
+		{"3/0", "", "division by zero"},
+		{"3/0.0", "", "division by zero"},
+		{"3/4/5", "", "rational expected"},
+		{"3/4x", "", "rational expected"},
+		{"08/2", "", "rational expected"},
+		{"1_/2", "", "rational expected"},
+		{"2i", "", "rational expected"},
+		{"x", "", "rational expected"},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		if sc.IsRat() != (test.err == "") {
+			t.Errorf("input %q: IsRat returned %v", test.in, test.err != "")
+		}
+		out := sc.Rat()
+		if test.err == "" {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if out.String() != test.out {
+				t.Errorf("input %q produced output %s instead of %s", test.in, out, test.out)
+			}
+		} else if err, ok := sc.Err().(*Error); !ok {
+			t.Errorf("input %q produced error %v", test.in, sc.Err())
+		} else if err.Message != test.err {
+			t.Errorf("input %q produced error %q instead of %q", test.in, err.Message, test.err)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		in  string
+		out float64
+		err string
+	}{
+		{"15%", 0.15, ""},
+		{"100%", 1, ""},
+		{"-50%", -0.5, ""},
+		{"2.5%", 0.025, ""},
+		{"15%,", 0.15, ""},
+		{"15", 0, "percentage expected"},
+		{"15 %", 0, "percentage expected"},
+		{"%", 0, "percentage expected"},
+		{"08%", 0, "percentage expected"},
+		{"1__0%", 0, "percentage expected"},
+		{"1e400%", 0, "float overflow"},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		if sc.IsPercent() != (test.err == "") {
+			t.Errorf("input %q: IsPercent returned %v", test.in, test.err != "")
+		}
+		out := sc.Percent()
+		if test.err == "" {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if out != test.out {
+				t.Errorf("input %q produced output %g instead of %g", test.in, out, test.out)
+			}
+		} else if err, ok := sc.Err().(*Error); !ok {
+			t.Errorf("input %q produced error %v", test.in, sc.Err())
+		} else if err.Message != test.err {
+			t.Errorf("input %q produced error %q instead of %q", test.in, err.Message, test.err)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package scanner
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// A NumberKind classifies number literals.
+type NumberKind int
+
+// The kinds of number literals.
+const (
+	IntNumber       NumberKind = iota // 42, 0x2a
+	FloatNumber                       // 4.2, 4e2, 0x1p-2
+	ImaginaryNumber                   // 4.2i
+)
+
+// A NumberLiteral is a number exactly as it appears in the input, without any conversion.
+type NumberLiteral struct {
+	Text string // the literal, including the sign
+	Kind NumberKind
+	Base int // 2, 8, 10 or 16; the base of the mantissa for floats
+}
+
+// Errors returned by the conversion methods of NumberLiteral.
+// ErrSyntax is only returned for literals that were not returned by Scanner.Number.
+// ErrNotFinite is returned for infinity and NaN, see InfNaN.
+var (
+	ErrNotInteger = errors.New("number is not an integer")
+	ErrRange      = errors.New("number out of range")
+	ErrSyntax     = errors.New("invalid number literal")
+	ErrNotFinite  = errors.New("number is not finite")
+)
+
+// IsNumber returns true if the next token is a number.
+func (s *Scanner) IsNumber() bool {
+	s.Expect("number")
+	_, ok := s.PeekNumber()
+	return ok
+}
+
+// PeekNumber returns the next token as a number literal, or false if the next token is not a number.
+// PeekNumber does not advance the scanner.
+func (s *Scanner) PeekNumber() (NumberLiteral, bool) {
+	text, kind, base := s.peekNumber()
+	if text == "" {
+		return NumberLiteral{}, false
+	}
+	return NumberLiteral{text, kind, base}, true
+}
+
+// Number returns the next token as a number literal, or causes an error if the next token is not a number.
+// Unlike Int or Float, Number does not convert the number, so no precision is lost.
+func (s *Scanner) Number() NumberLiteral {
+	n, ok := s.PeekNumber()
+	if !ok {
+		s.Fail("number expected")
+		return NumberLiteral{}
+	}
+	s.pos += len(n.Text)
+	s.space()
+	return n
+}
+
+// mantissa returns the literal without the imaginary suffix, and the kind of that number.
+func (n NumberLiteral) mantissa() (string, NumberKind) {
+	if n.Kind != ImaginaryNumber {
+		return n.Text, n.Kind
+	}
+	text := n.Text[:len(n.Text)-1]
+	if n.Base != 10 && !strings.ContainsAny(text, ".pP") {
+		return text, IntNumber
+	}
+	// like floats, decimal imaginary literals may have leading zeros
+	return text, FloatNumber
+}
+
+// conversionError returns ErrSyntax if n is not a valid number literal, and ErrRange otherwise.
+func (n NumberLiteral) conversionError() error {
+	if l, kind, _ := scanNumber(n.Text, GoNumbers|PlusSign|InfNaN); l == 0 || l != len(n.Text) || kind != n.Kind {
+		return ErrSyntax
+	}
+	return ErrRange
+}
+
+// infNaN returns whether the number is infinity or NaN, and whether it is negative.
+func (n NumberLiteral) infNaN() (inf, nan, neg bool) {
+	text := strings.TrimLeft(n.Text, "+-")
+	if l := scanInfNaN(text, false); l == 0 || l != len(text) {
+		return false, false, false
+	}
+	return text[0]|0x20 == 'i', text[0]|0x20 == 'n', n.Text[0] == '-'
+}
+
+// Rat returns the exact value of the number.
+// For imaginary literals, Rat returns the value of the imaginary part.
+// For infinity and NaN, Rat returns ErrNotFinite.
+func (n NumberLiteral) Rat() (*big.Rat, error) {
+	if inf, nan, _ := n.infNaN(); inf || nan {
+		return nil, ErrNotFinite
+	}
+	text, kind := n.mantissa()
+	if kind == IntNumber {
+		i, ok := new(big.Int).SetString(text, 0)
+		if !ok {
+			return nil, n.conversionError()
+		}
+		return new(big.Rat).SetInt(i), nil
+	}
+	r, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return nil, n.conversionError()
+	}
+	return r, nil
+}
+
+// BigFloat returns the value of the number, rounded to the given precision in bits.
+// If prec is 0, integers are converted exactly and floats use a precision of 64 bits.
+// For imaginary literals, BigFloat returns the value of the imaginary part.
+// Infinity is converted to an infinite big.Float, for NaN BigFloat returns ErrNotFinite.
+func (n NumberLiteral) BigFloat(prec uint) (*big.Float, error) {
+	if inf, nan, neg := n.infNaN(); nan {
+		return nil, ErrNotFinite
+	} else if inf {
+		return new(big.Float).SetPrec(prec).SetInf(neg), nil
+	}
+	text, kind := n.mantissa()
+	if kind == IntNumber {
+		i, ok := new(big.Int).SetString(text, 0)
+		if !ok {
+			return nil, n.conversionError()
+		}
+		return new(big.Float).SetPrec(prec).SetInt(i), nil
+	}
+	f, _, err := big.ParseFloat(text, 0, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, n.conversionError()
+	}
+	return f, nil
+}
+
+// Int64 returns the value of the number as an int64.
+// Floats are accepted if their value is an integer, so 1.0 and 1e3 are valid, but 1.5 is not.
+// Int64 returns ErrNotInteger for imaginary literals, infinity, NaN and numbers that are not integers,
+// ErrRange for integers that do not fit into an int64, and ErrSyntax for invalid literals.
+func (n NumberLiteral) Int64() (int64, error) {
+	if inf, nan, _ := n.infNaN(); n.Kind == ImaginaryNumber || inf || nan {
+		return 0, ErrNotInteger
+	}
+	r, err := n.Rat()
+	if err != nil {
+		return 0, err
+	}
+	if !r.IsInt() {
+		return 0, ErrNotInteger
+	}
+	if !r.Num().IsInt64() {
+		return 0, ErrRange
+	}
+	return r.Num().Int64(), nil
+}
@@ -0,0 +1,153 @@
+package scanner_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	. "github.com/jfreymuth/scanner"
+)
+
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		in   string
+		out  NumberLiteral
+		rat  string
+		i    int64
+		ierr error
+	}{
+		{"0", NumberLiteral{"0", IntNumber, 10}, "0/1", 0, nil},
+		{"-42", NumberLiteral{"-42", IntNumber, 10}, "-42/1", -42, nil},
+		{"0x2A", NumberLiteral{"0x2A", IntNumber, 16}, "42/1", 42, nil},
+		{"0b101", NumberLiteral{"0b101", IntNumber, 2}, "5/1", 5, nil},
+		{"0o17", NumberLiteral{"0o17", IntNumber, 8}, "15/1", 15, nil},
+		{"017", NumberLiteral{"017", IntNumber, 8}, "15/1", 15, nil},
+		{"1_000", NumberLiteral{"1_000", IntNumber, 10}, "1000/1", 1000, nil},
+		{"0.1", NumberLiteral{"0.1", FloatNumber, 10}, "1/10", 0, ErrNotInteger},
+		{"19.99", NumberLiteral{"19.99", FloatNumber, 10}, "1999/100", 0, ErrNotInteger},
+		{"1e3", NumberLiteral{"1e3", FloatNumber, 10}, "1000/1", 1000, nil},
+		{"2.50", NumberLiteral{"2.50", FloatNumber, 10}, "5/2", 0, ErrNotInteger},
+		{"017.5", NumberLiteral{"017.5", FloatNumber, 10}, "35/2", 0, ErrNotInteger},
+		{"0x1p-2", NumberLiteral{"0x1p-2", FloatNumber, 16}, "1/4", 0, ErrNotInteger},
+		{"2.5i", NumberLiteral{"2.5i", ImaginaryNumber, 10}, "5/2", 0, ErrNotInteger},
+		{"0x10i", NumberLiteral{"0x10i", ImaginaryNumber, 16}, "16/1", 0, ErrNotInteger},
+		{"0123i", NumberLiteral{"0123i", ImaginaryNumber, 10}, "123/1", 0, ErrNotInteger},
+		{"08.5", NumberLiteral{"08.5", FloatNumber, 10}, "17/2", 0, ErrNotInteger},
+		{"08e1", NumberLiteral{"08e1", FloatNumber, 10}, "80/1", 80, nil},
+		{"0_17", NumberLiteral{"0_17", IntNumber, 8}, "15/1", 15, nil},
+		{"123456789012345678901234567890", NumberLiteral{"123456789012345678901234567890", IntNumber, 10}, "123456789012345678901234567890/1", 0, ErrRange},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		if !sc.IsNumber() {
+			t.Errorf("input %q: IsNumber returned false", test.in)
+		}
+		out := sc.Number()
+		if sc.Err() != nil {
+			t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			continue
+		}
+		if out != test.out {
+			t.Errorf("input %q produced %v instead of %v", test.in, out, test.out)
+		}
+		if r, err := out.Rat(); err != nil {
+			t.Errorf("input %q: Rat returned error: %s", test.in, err)
+		} else if r.String() != test.rat {
+			t.Errorf("input %q: Rat returned %s instead of %s", test.in, r, test.rat)
+		}
+		if f, err := out.BigFloat(0); err != nil {
+			t.Errorf("input %q: BigFloat returned error: %s", test.in, err)
+		} else if r, _ := out.Rat(); out.Kind == IntNumber && f.Cmp(new(big.Float).SetRat(r)) != 0 {
+			t.Errorf("input %q: BigFloat returned inexact %s", test.in, f.Text('g', -1))
+		}
+		if i, err := out.Int64(); err != test.ierr {
+			t.Errorf("input %q: Int64 returned error %v instead of %v", test.in, err, test.ierr)
+		} else if err == nil && i != test.i {
+			t.Errorf("input %q: Int64 returned %d instead of %d", test.in, i, test.i)
+		}
+	}
+
+	for _, in := range []string{"", "a", "1a", "0x", "1e", "_1", "1__0", "1_", "08", "-019", "0_8", "0x1.8", "0x1.8i", "0x.8"} {
+		sc := FromString(in)
+		if sc.IsNumber() {
+			t.Errorf("input %q: IsNumber returned true", in)
+		}
+		sc.Number()
+		if sc.Err() == nil {
+			t.Errorf("input %q should produce an error", in)
+		}
+	}
+}
+
+func TestNumberLiteralErrors(t *testing.T) {
+	tests := []struct {
+		in  NumberLiteral
+		err error
+	}{
+		{NumberLiteral{"08", IntNumber, 8}, ErrSyntax},
+		{NumberLiteral{"1__0", IntNumber, 10}, ErrSyntax},
+		{NumberLiteral{"1.5x", FloatNumber, 10}, ErrSyntax},
+		{NumberLiteral{"", IntNumber, 10}, ErrSyntax},
+		{NumberLiteral{"1e99999999999", FloatNumber, 10}, ErrRange},
+	}
+
+	for _, test := range tests {
+		if _, err := test.in.Rat(); err != test.err {
+			t.Errorf("literal %q: Rat returned error %v instead of %v", test.in.Text, err, test.err)
+		}
+		if _, err := test.in.Int64(); err != test.err {
+			t.Errorf("literal %q: Int64 returned error %v instead of %v", test.in.Text, err, test.err)
+		}
+	}
+}
+
+func TestNumberInfNaN(t *testing.T) {
+	tests := []struct {
+		in  string
+		f   string
+		err error
+	}{
+		{"inf", "+Inf", nil},
+		{"-Infinity", "-Inf", nil},
+		{"+INF", "+Inf", nil},
+		{"nan", "", ErrNotFinite},
+		{"NaN", "", ErrNotFinite},
+	}
+
+	for _, test := range tests {
+		opt := DefaultOptions()
+		opt.Numbers |= InfNaN | PlusSign
+		sc := NewWithOptions(strings.NewReader(test.in), opt)
+		n := sc.Number()
+		if sc.Err() != nil {
+			t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			continue
+		}
+		if _, err := n.Rat(); err != ErrNotFinite {
+			t.Errorf("input %q: Rat returned error %v instead of %v", test.in, err, ErrNotFinite)
+		}
+		if _, err := n.Int64(); err != ErrNotInteger {
+			t.Errorf("input %q: Int64 returned error %v instead of %v", test.in, err, ErrNotInteger)
+		}
+		if f, err := n.BigFloat(0); err != test.err {
+			t.Errorf("input %q: BigFloat returned error %v instead of %v", test.in, err, test.err)
+		} else if err == nil && f.String() != test.f {
+			t.Errorf("input %q: BigFloat returned %s instead of %s", test.in, f, test.f)
+		}
+	}
+}
+
+func TestNumberPrecision(t *testing.T) {
+	sc := FromString("0.1 123456789012345678901234567890.5")
+	a, b := sc.Number(), sc.Number()
+	ra, _ := a.Rat()
+	rb, _ := b.Rat()
+	if sum := new(big.Rat).Add(ra, rb).FloatString(1); sum != "123456789012345678901234567890.6" {
+		t.Errorf("sum is %s", sum)
+	}
+	f, _ := b.BigFloat(128)
+	if s := f.Text('f', 1); s != "123456789012345678901234567890.5" {
+		t.Errorf("BigFloat(128) returned %s", s)
+	}
+}
@@ -0,0 +1,125 @@
+package scanner
+
+// An OperatorSet is a precompiled set of operators that can be matched efficiently.
+// When several operators match, the longest one is used.
+type OperatorSet struct {
+	ops  []string
+	root opNode
+}
+
+type opNode struct {
+	next map[byte]*opNode
+	end  bool
+}
+
+// NewOperatorSet creates a set containing the given operators. Empty operators are ignored.
+func NewOperatorSet(ops ...string) *OperatorSet {
+	o := &OperatorSet{}
+	for _, op := range ops {
+		if op == "" {
+			continue
+		}
+		o.ops = append(o.ops, op)
+		n := &o.root
+		for i := 0; i < len(op); i++ {
+			if n.next == nil {
+				n.next = make(map[byte]*opNode)
+			}
+			c := n.next[op[i]]
+			if c == nil {
+				c = &opNode{}
+				n.next[op[i]] = c
+			}
+			n = c
+		}
+		n.end = true
+	}
+	return o
+}
+
+// Operators returns the operators in the set, in the order they were given to NewOperatorSet.
+func (o *OperatorSet) Operators() []string {
+	return append([]string(nil), o.ops...)
+}
+
+// Match returns the length of the longest operator that is a prefix of str, or 0 if there is none.
+func (o *OperatorSet) Match(str string) int {
+	l := 0
+	n := &o.root
+	for i := 0; i < len(str) && n.next != nil; i++ {
+		if n = n.next[str[i]]; n == nil {
+			break
+		}
+		if n.end {
+			l = i + 1
+		}
+	}
+	return l
+}
+
+// EatAny consumes the longest of the given operators the scanner's input starts with.
+// It returns the operator and true, or "" and false if none of the operators match.
+// The operators should not contain whitespace.
+func (s *Scanner) EatAny(ops ...string) (string, bool) {
+	l := 0
+	for _, op := range ops {
+		if len(op) > l && s.has(op) {
+			l = len(op)
+		}
+	}
+	if l == 0 {
+		for _, op := range ops {
+			s.expectToken(op)
+		}
+	}
+	return s.eat(l)
+}
+
+// DemandAny consumes the longest of the given operators, or causes an error listing all of them if none match.
+// The operators should not contain whitespace.
+func (s *Scanner) DemandAny(ops ...string) string {
+	op, ok := s.EatAny(ops...)
+	if !ok {
+		s.Failf("%s expected", quoteAll(ops))
+	}
+	return op
+}
+
+// EatOperator consumes the longest operator from the set the scanner's input starts with.
+// It returns the operator and true, or "" and false if none of the operators match.
+func (s *Scanner) EatOperator(set *OperatorSet) (string, bool) {
+	n := set.Match(s.line[s.pos:])
+	if n == 0 {
+		s.expect(expectation{set: set})
+	}
+	return s.eat(n)
+}
+
+// DemandOperator consumes the longest operator from the set, or causes an error listing all of them if none match.
+func (s *Scanner) DemandOperator(set *OperatorSet) string {
+	op, ok := s.EatOperator(set)
+	if !ok {
+		s.Failf("%s expected", quoteAll(set.ops))
+	}
+	return op
+}
+
+// eat consumes n bytes and returns them, or returns "" and false if n is 0.
+func (s *Scanner) eat(n int) (string, bool) {
+	if n == 0 {
+		return "", false
+	}
+	str := s.line[s.pos : s.pos+n]
+	s.pos += n
+	s.space()
+	return str, true
+}
+
+// quoteAll formats a list of tokens like "'a', 'b' or 'c'".
+func quoteAll(list []string) string {
+	q := make([]string, len(list))
+	for i, str := range list {
+		q[i] = "'" + str + "'"
+	}
+	return joinOr(q)
+}
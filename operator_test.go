@@ -0,0 +1,83 @@
+package scanner_test
+
+import (
+	"testing"
+
+	. "github.com/jfreymuth/scanner"
+)
+
+func TestEatAny(t *testing.T) {
+	ops := []string{"<", "<=", "<<", "<<=", "=", "=="}
+	tests := []struct {
+		in  string
+		out []string
+		end bool
+	}{
+		{"<", []string{"<"}, true},
+		{"<=", []string{"<="}, true},
+		{"<<=", []string{"<<="}, true},
+		{"<<<", []string{"<<", "<"}, true},
+		{"<==", []string{"<=", "="}, true},
+		{"< =", []string{"<", "="}, true},
+		{"===", []string{"==", "="}, true},
+		{"<<=<", []string{"<<=", "<"}, true},
+		{"a", nil, false},
+		{"", nil, true},
+	}
+
+	set := NewOperatorSet(ops...)
+	for _, test := range tests {
+		for _, precompiled := range []bool{false, true} {
+			sc := FromString(test.in)
+			var out []string
+			for {
+				var op string
+				var ok bool
+				if precompiled {
+					op, ok = sc.EatOperator(set)
+				} else {
+					op, ok = sc.EatAny(ops...)
+				}
+				if !ok {
+					break
+				}
+				out = append(out, op)
+			}
+			if len(out) != len(test.out) {
+				t.Errorf("input %q produced %q instead of %q", test.in, out, test.out)
+				continue
+			}
+			for i := range out {
+				if out[i] != test.out[i] {
+					t.Errorf("input %q produced %q instead of %q", test.in, out, test.out)
+					break
+				}
+			}
+			if sc.End() != test.end {
+				t.Errorf("input %q: End returned %v", test.in, !test.end)
+			}
+		}
+	}
+}
+
+func TestDemandAny(t *testing.T) {
+	const msg = "'+', '+=' or '++' expected"
+	sc := FromString("++ -")
+	if op := sc.DemandAny("+", "+=", "++"); op != "++" {
+		t.Errorf("produced %q instead of \"++\"", op)
+	}
+	sc.DemandAny("+", "+=", "++")
+	if err, ok := sc.Err().(*Error); !ok || err.Message != msg {
+		t.Errorf("produced error %v instead of %q", sc.Err(), msg)
+	}
+
+	sc = FromString("++ -")
+	set := NewOperatorSet("+", "+=", "++")
+	if op := sc.DemandOperator(set); op != "++" {
+		t.Errorf("produced %q instead of \"++\"", op)
+	}
+	sc.DemandOperator(set)
+	if err, ok := sc.Err().(*Error); !ok || err.Message != msg {
+		t.Errorf("produced error %v instead of %q", sc.Err(), msg)
+	}
+}
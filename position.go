@@ -0,0 +1,144 @@
+package scanner
+
+import (
+	"fmt"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A Position describes a location in a scanner's input.
+type Position struct {
+	Filename string // the file name given in Options, if any
+	Offset   int    // byte offset, starting at 0
+	Line     int    // line number, starting at 1
+	Column   int    // column number in runes, starting at 1
+}
+
+// String returns the position in the form "file:line:column", or "line:column" if there is no file name.
+func (p Position) String() string {
+	if p.Filename == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+// Pos returns the position of the next token.
+// At the end of the input, the position is after the last character of the last line,
+// and its offset is the length of the input.
+func (s *Scanner) Pos() Position {
+	off := s.off + s.pos
+	if s.err == io.EOF {
+		off = s.srcOff
+	}
+	return Position{s.opt.Filename, off, s.ln, s.column()}
+}
+
+// column returns the column of the current position in runes.
+// To avoid rescanning long lines, it only counts the runes after the position of the previous call, if possible.
+func (s *Scanner) column() int {
+	if s.line != s.colLine || s.pos < s.colPos {
+		s.colLine, s.colPos, s.colRunes = s.line, 0, 0
+	}
+	s.colRunes += utf8.RuneCountInString(s.line[s.colPos:s.pos])
+	s.colPos = s.pos
+	return s.colRunes + 1
+}
+
+// UTF16Column returns the column of the error in UTF-16 code units, starting at 1.
+func (e *Error) UTF16Column() int {
+	return e.Position.UTF16Column(e.Line)
+}
+
+// UTF16Column returns the column of the position in UTF-16 code units, starting at 1,
+// as used by many editors. line must be the text of the line the position refers to.
+func (p Position) UTF16Column(line string) int {
+	col := 1
+	for _, r := range line[:columnIndex(line, p.Column)] {
+		if r >= 0x10000 {
+			col += 2
+		} else {
+			col++
+		}
+	}
+	return col
+}
+
+// columnIndex returns the byte index in line corresponding to a column in runes.
+func columnIndex(line string, col int) int {
+	for i := range line {
+		if col <= 1 {
+			return i
+		}
+		col--
+	}
+	return len(line)
+}
+
+// runeWidth returns the number of terminal columns used to display r.
+func runeWidth(r rune) int {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case unicode.Is(wide, r):
+		return 2
+	}
+	return 1
+}
+
+// wide approximately contains the characters with the East Asian Width property Wide or Fullwidth.
+var wide = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x1100, 0x115f, 1},
+		{0x231a, 0x231b, 1},
+		{0x2329, 0x232a, 1},
+		{0x23e9, 0x23ec, 1},
+		{0x23f0, 0x23f3, 3},
+		{0x25fd, 0x25fe, 1},
+		{0x2614, 0x2615, 1},
+		{0x2648, 0x2653, 1},
+		{0x267f, 0x2693, 20},
+		{0x26a1, 0x26aa, 9},
+		{0x26ab, 0x26bd, 18},
+		{0x26be, 0x26c4, 6},
+		{0x26c5, 0x26ce, 9},
+		{0x26d4, 0x26ea, 22},
+		{0x26f2, 0x26f3, 1},
+		{0x26f5, 0x26fa, 5},
+		{0x26fd, 0x2705, 8},
+		{0x270a, 0x270b, 1},
+		{0x2728, 0x274c, 36},
+		{0x274e, 0x2753, 5},
+		{0x2754, 0x2755, 1},
+		{0x2757, 0x2795, 62},
+		{0x2796, 0x2797, 1},
+		{0x27b0, 0x27bf, 15},
+		{0x2b1b, 0x2b1c, 1},
+		{0x2b50, 0x2b55, 5},
+		{0x2e80, 0x303e, 1},
+		{0x3041, 0x33ff, 1},
+		{0x3400, 0x4dbf, 1},
+		{0x4e00, 0xa4cf, 1},
+		{0xa960, 0xa97f, 1},
+		{0xac00, 0xd7a3, 1},
+		{0xf900, 0xfaff, 1},
+		{0xfe10, 0xfe19, 1},
+		{0xfe30, 0xfe6f, 1},
+		{0xff00, 0xff60, 1},
+		{0xffe0, 0xffe6, 1},
+	},
+	R32: []unicode.Range32{
+		{0x16fe0, 0x16fe4, 1},
+		{0x17000, 0x18cff, 1},
+		{0x1b000, 0x1b2ff, 1},
+		{0x1f004, 0x1f0cf, 203},
+		{0x1f18e, 0x1f191, 3},
+		{0x1f192, 0x1f19a, 1},
+		{0x1f200, 0x1f251, 1},
+		{0x1f300, 0x1f64f, 1},
+		{0x1f680, 0x1f6ff, 1},
+		{0x1f900, 0x1f9ff, 1},
+		{0x20000, 0x2fffd, 1},
+		{0x30000, 0x3fffd, 1},
+	},
+}
@@ -0,0 +1,101 @@
+package scanner_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/jfreymuth/scanner"
+)
+
+func TestPos(t *testing.T) {
+	tests := []struct {
+		in    string
+		skip  int
+		out   Position
+		utf16 int
+	}{
+		{"a", 0, Position{"", 0, 1, 1}, 1},
+		{"a b", 1, Position{"", 2, 1, 3}, 3},
+		{"a\nb", 1, Position{"", 2, 2, 1}, 1},
+		{"a\r\n  b", 1, Position{"", 5, 2, 3}, 3},
+		{"ä ö", 1, Position{"", 3, 1, 3}, 3},
+		{"𝔸 b", 1, Position{"", 5, 1, 3}, 4},
+		{"a /* \n */ b", 1, Position{"", 10, 2, 5}, 5},
+		{"a\n", 1, Position{"", 2, 1, 2}, 2},
+		{"a", 1, Position{"", 1, 1, 2}, 2},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		for i := 0; i < test.skip; i++ {
+			sc.Rune()
+		}
+		if pos := sc.Pos(); pos != test.out {
+			t.Errorf("input %q produced position %v (offset %d) instead of %v (offset %d)", test.in, pos, pos.Offset, test.out, test.out.Offset)
+		}
+		sc.Fail("test")
+		err := sc.Err().(*Error)
+		if err.Position != test.out {
+			t.Errorf("input %q produced error at %v (offset %d) instead of %v (offset %d)", test.in, err.Position, err.Offset, test.out, test.out.Offset)
+		}
+		if col := err.UTF16Column(); col != test.utf16 {
+			t.Errorf("input %q produced UTF-16 column %d instead of %d", test.in, col, test.utf16)
+		}
+	}
+}
+
+func TestPosFilename(t *testing.T) {
+	sc := NewWithOptions(strings.NewReader("a\n b"), Options{Filename: "test.txt"})
+	sc.Demand("a")
+	if pos := sc.Pos().String(); pos != "test.txt:2:2" {
+		t.Errorf("produced position %s instead of test.txt:2:2", pos)
+	}
+}
+
+func TestPosReset(t *testing.T) {
+	sc := FromString("äa ö b\nc")
+	m := sc.Mark()
+	sc.Ident()
+	sc.Ident()
+	if pos := sc.Pos(); pos.Column != 6 {
+		t.Errorf("produced column %d instead of 6", pos.Column)
+	}
+	sc.Reset(m)
+	if pos := sc.Pos(); pos.Column != 1 {
+		t.Errorf("produced column %d after Reset instead of 1", pos.Column)
+	}
+	sc.Ident()
+	sc.Ident()
+	sc.Ident()
+	if pos := sc.Pos(); pos.Column != 1 || pos.Line != 2 {
+		t.Errorf("produced position %v instead of 2:1", pos)
+	}
+}
+
+func TestPosUTF16Column(t *testing.T) {
+	line := "𝔸ä b"
+	sc := FromString(line)
+	sc.Ident()
+	if col := sc.Pos().UTF16Column(line); col != 5 {
+		t.Errorf("produced UTF-16 column %d instead of 5", col)
+	}
+}
+
+func TestPosEOF(t *testing.T) {
+	sc := FromString("x = 1 +")
+	sc.Demand("x")
+	sc.Demand("=")
+	sc.Int()
+	sc.Demand("+")
+	sc.Int()
+	err, ok := sc.Err().(*Error)
+	if !ok {
+		t.Fatalf("produced error %v", sc.Err())
+	}
+	if p := (Position{"", 7, 1, 8}); err.Position != p {
+		t.Errorf("produced error at %v (offset %d) instead of %v (offset %d)", err.Position, err.Offset, p, p.Offset)
+	}
+	if ind := err.PositionIndicator(); ind != "Line 1: integer expected\nx = 1 +\n       ^" {
+		t.Errorf("produced indicator %q", ind)
+	}
+}
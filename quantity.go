@@ -0,0 +1,232 @@
+package scanner
+
+import (
+	"math/big"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ByteUnits are the units accepted by ByteSize,
+// with decimal prefixes like "kB" and "MB", and binary prefixes like "KiB" and "MiB".
+var ByteUnits = map[string]uint64{
+	"B":  1,
+	"kB": 1e3, "KB": 1e3, "MB": 1e6, "GB": 1e9, "TB": 1e12, "PB": 1e15, "EB": 1e18,
+	"KiB": 1 << 10, "MiB": 1 << 20, "GiB": 1 << 30, "TiB": 1 << 40, "PiB": 1 << 50, "EiB": 1 << 60,
+}
+
+var durationUnits = map[string]bool{"ns": true, "us": true, "µs": true, "μs": true, "ms": true, "s": true, "m": true, "h": true}
+
+// IsQuantity returns true if the next token is a number followed by one of the units.
+func (s *Scanner) IsQuantity(units map[string]float64) bool {
+	s.Expect("quantity")
+	_, ok := s.PeekQuantity(units)
+	return ok
+}
+
+// PeekQuantity returns the next token as a quantity, or (0, false) if the next token is not a number followed by one of the units.
+// PeekQuantity does not advance the scanner.
+func (s *Scanner) PeekQuantity(units map[string]float64) (float64, bool) {
+	f, _, msg := s.peekQuantity(units)
+	return f, msg == ""
+}
+
+// Quantity parses a number followed by a unit, like "12px" or "1.5em", and returns the number multiplied by the unit's factor.
+// There must not be any whitespace between the number and the unit.
+// A unit consists of letters, '%' and symbols like '°'.
+// If units contains the empty string, numbers without a unit are accepted.
+func (s *Scanner) Quantity(units map[string]float64) float64 {
+	f, n, msg := s.peekQuantity(units)
+	if msg != "" {
+		s.Fail(msg)
+		return 0
+	}
+	s.pos += n
+	s.space()
+	return f
+}
+
+func (s *Scanner) peekQuantity(units map[string]float64) (float64, int, string) {
+	num, unit, n := s.scanQuantity()
+	if n == 0 {
+		return 0, 0, "quantity expected"
+	}
+	factor, ok := units[unit]
+	if !ok {
+		return 0, 0, unitError(unit)
+	}
+	f, err := parseFloat(num.Text, num.Kind)
+	if err != nil {
+		return 0, 0, "float overflow"
+	}
+	return f * factor, n, ""
+}
+
+// IsByteSize returns true if the next token is a byte size.
+func (s *Scanner) IsByteSize() bool {
+	s.Expect("byte size")
+	_, ok := s.PeekByteSize()
+	return ok
+}
+
+// PeekByteSize returns the next token as a byte size, or (0, false) if the next token is not a byte size.
+// PeekByteSize does not advance the scanner.
+func (s *Scanner) PeekByteSize() (uint64, bool) {
+	b, _, msg := s.peekByteSize()
+	return b, msg == ""
+}
+
+// ByteSize parses a number of bytes, optionally followed by one of the units in ByteUnits, like "512MiB" or "1.5kB".
+// There must not be any whitespace between the number and the unit.
+// The result must be a whole number of bytes that fits into an uint64.
+func (s *Scanner) ByteSize() uint64 {
+	b, n, msg := s.peekByteSize()
+	if msg != "" {
+		s.Fail(msg)
+		return 0
+	}
+	s.pos += n
+	s.space()
+	return b
+}
+
+func (s *Scanner) peekByteSize() (uint64, int, string) {
+	num, unit, n := s.scanQuantity()
+	if n == 0 {
+		return 0, 0, "byte size expected"
+	}
+	factor := uint64(1)
+	if unit != "" {
+		var ok bool
+		if factor, ok = ByteUnits[unit]; !ok {
+			return 0, 0, unitError(unit)
+		}
+	}
+	r, err := num.Rat()
+	if err != nil {
+		return 0, 0, "byte size overflow"
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).SetUint64(factor)))
+	switch {
+	case r.Sign() < 0:
+		return 0, 0, "negative byte size"
+	case !r.IsInt():
+		return 0, 0, "byte size is not a whole number of bytes"
+	case !r.Num().IsUint64():
+		return 0, 0, "byte size overflow"
+	}
+	return r.Num().Uint64(), n, ""
+}
+
+// scanQuantity returns the number and unit at the start of the scanner's input, and their combined length.
+// Imaginary literals, infinity and NaN are not accepted, so that units may start with 'i' or 'n'.
+func (s *Scanner) scanQuantity() (NumberLiteral, string, int) {
+	line := s.line[s.pos:]
+	n, kind, base := scanNumber(line, s.opt.Numbers&^(ImaginaryLiterals|InfNaN))
+	if n == 0 {
+		return NumberLiteral{}, "", 0
+	}
+	u := n
+	for u < len(line) {
+		r, size := utf8.DecodeRuneInString(line[u:])
+		if !isUnit(r) {
+			break
+		}
+		u += size
+	}
+	if r, _ := utf8.DecodeRuneInString(line[u:]); r == '_' || r == '.' || unicode.IsDigit(r) {
+		return NumberLiteral{}, "", 0
+	}
+	return NumberLiteral{line[:n], kind, base}, line[n:u], u
+}
+
+func isUnit(r rune) bool {
+	return r == '%' || unicode.IsLetter(r) || unicode.In(r, unicode.So, unicode.Sc)
+}
+
+func unitError(unit string) string {
+	if unit == "" {
+		return "unit expected"
+	}
+	return "unknown unit '" + unit + "'"
+}
+
+// IsDuration returns true if the next token is a duration.
+func (s *Scanner) IsDuration() bool {
+	s.Expect("duration")
+	_, ok := s.PeekDuration()
+	return ok
+}
+
+// PeekDuration returns the next token as a duration, or (0, false) if the next token is not a duration.
+// PeekDuration does not advance the scanner.
+func (s *Scanner) PeekDuration() (time.Duration, bool) {
+	d, _, msg := s.peekDuration()
+	return d, msg == ""
+}
+
+// Duration parses a duration in the format accepted by time.ParseDuration, like "30s", "1.5h" or "1h30m".
+// The units are "ns", "us" (or "µs"), "ms", "s", "m" and "h".
+func (s *Scanner) Duration() time.Duration {
+	d, n, msg := s.peekDuration()
+	if msg != "" {
+		s.Fail(msg)
+		return 0
+	}
+	s.pos += n
+	s.space()
+	return d
+}
+
+func (s *Scanner) peekDuration() (time.Duration, int, string) {
+	line := s.line[s.pos:]
+	i := 0
+	if strings.HasPrefix(line, "-") {
+		i++
+	}
+	for {
+		start := i
+		for i < len(line) && line[i] >= '0' && line[i] <= '9' {
+			i++
+		}
+		if i < len(line) && line[i] == '.' {
+			i++
+			for i < len(line) && line[i] >= '0' && line[i] <= '9' {
+				i++
+			}
+		}
+		if i == start || line[start:i] == "." {
+			if start == 0 || start == 1 && line[0] == '-' {
+				return 0, 0, "duration expected"
+			}
+			i = start
+			break
+		}
+		u := i
+		for u < len(line) {
+			r, size := utf8.DecodeRuneInString(line[u:])
+			if !unicode.IsLetter(r) {
+				break
+			}
+			u += size
+		}
+		if unit := line[i:u]; unit == "" {
+			if strings.TrimPrefix(line[:i], "-") == "0" {
+				break
+			}
+			return 0, 0, "duration unit expected"
+		} else if !durationUnits[unit] {
+			return 0, 0, "unknown duration unit '" + unit + "'"
+		}
+		i = u
+	}
+	if r, _ := utf8.DecodeRuneInString(line[i:]); r == '_' || r == '.' {
+		return 0, 0, "duration expected"
+	}
+	d, err := time.ParseDuration(line[:i])
+	if err != nil {
+		return 0, 0, "duration overflow"
+	}
+	return d, i, ""
+}
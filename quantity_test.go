@@ -0,0 +1,143 @@
+package scanner_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/jfreymuth/scanner"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		in  string
+		out time.Duration
+		err string
+	}{
+		{"30s", 30 * time.Second, ""},
+		{"1h30m", 90 * time.Minute, ""},
+		{"1.5h", 90 * time.Minute, ""},
+		{"-2m", -2 * time.Minute, ""},
+		{".5s", 500 * time.Millisecond, ""},
+		{"100ms x", 100 * time.Millisecond, ""},
+		{"1µs", time.Microsecond, ""},
+		{"1us2ns", time.Microsecond + 2*time.Nanosecond, ""},
+		{"0", 0, ""},
+		{"30s,", 30 * time.Second, ""},
+		{"30", 0, "duration unit expected"},
+		{"1h30", 0, "duration unit expected"},
+		{"30x", 0, "unknown duration unit 'x'"},
+		{"30sec", 0, "unknown duration unit 'sec'"},
+		{"s", 0, "duration expected"},
+		{"-", 0, "duration expected"},
+		{"1s.", 0, "duration expected"},
+		{"9999999999h", 0, "duration overflow"},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		if sc.IsDuration() != (test.err == "") {
+			t.Errorf("input %q: IsDuration returned %v", test.in, test.err != "")
+		}
+		out := sc.Duration()
+		if test.err == "" {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if out != test.out {
+				t.Errorf("input %q produced output %s instead of %s", test.in, out, test.out)
+			}
+		} else if err, ok := sc.Err().(*Error); !ok {
+			t.Errorf("input %q produced error %v", test.in, sc.Err())
+		} else if err.Message != test.err {
+			t.Errorf("input %q produced error %q instead of %q", test.in, err.Message, test.err)
+		}
+	}
+}
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		in  string
+		out uint64
+		err string
+	}{
+		{"512", 512, ""},
+		{"512B", 512, ""},
+		{"1kB", 1000, ""},
+		{"1KB", 1000, ""},
+		{"512MiB", 512 << 20, ""},
+		{"1.5KiB", 1536, ""},
+		{"2GB", 2e9, ""},
+		{"0x10KiB", 16 << 10, ""},
+		{"16EiB", 0, "byte size overflow"},
+		{"15EiB", 15 << 60, ""},
+		{"1.5B", 0, "byte size is not a whole number of bytes"},
+		{"-1KB", 0, "negative byte size"},
+		{"1kiB", 0, "unknown unit 'kiB'"},
+		{"1 KB", 1, ""},
+		{"KB", 0, "byte size expected"},
+		{"1KB2", 0, "byte size expected"},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		if sc.IsByteSize() != (test.err == "") {
+			t.Errorf("input %q: IsByteSize returned %v", test.in, test.err != "")
+		}
+		out := sc.ByteSize()
+		if test.err == "" {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if out != test.out {
+				t.Errorf("input %q produced output %d instead of %d", test.in, out, test.out)
+			}
+		} else if err, ok := sc.Err().(*Error); !ok {
+			t.Errorf("input %q produced error %v", test.in, sc.Err())
+		} else if err.Message != test.err {
+			t.Errorf("input %q produced error %q instead of %q", test.in, err.Message, test.err)
+		}
+	}
+}
+
+func TestQuantity(t *testing.T) {
+	units := map[string]float64{"px": 1, "em": 16, "in": 96, "%": 0.01, "°": 1}
+	tests := []struct {
+		in  string
+		out float64
+		err string
+	}{
+		{"12px", 12, ""},
+		{"1.5em", 24, ""},
+		{"1em", 16, ""},
+		{"2in", 192, ""},
+		{"-2px", -2, ""},
+		{"50%", 0.5, ""},
+		{"90°", 90, ""},
+		{"12px;", 12, ""},
+		{"12pt", 0, "unknown unit 'pt'"},
+		{"12", 0, "unit expected"},
+		{"px", 0, "quantity expected"},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		if sc.IsQuantity(units) != (test.err == "") {
+			t.Errorf("input %q: IsQuantity returned %v", test.in, test.err != "")
+		}
+		out := sc.Quantity(units)
+		if test.err == "" {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if out != test.out {
+				t.Errorf("input %q produced output %g instead of %g", test.in, out, test.out)
+			}
+		} else if err, ok := sc.Err().(*Error); !ok {
+			t.Errorf("input %q produced error %v", test.in, sc.Err())
+		} else if err.Message != test.err {
+			t.Errorf("input %q produced error %q instead of %q", test.in, err.Message, test.err)
+		}
+	}
+
+	sc := FromString("12")
+	if out := sc.Quantity(map[string]float64{"": 1, "k": 1000}); out != 12 || sc.Err() != nil {
+		t.Errorf("unitless quantity produced %g, %v", out, sc.Err())
+	}
+}
@@ -13,6 +13,36 @@ func (s *Scanner) String() string {
 	return s.Quote("\"", "\"", GoEscaper('"'))
 }
 
+// IsString returns true if the next token is a string enclosed in double quotes.
+func (s *Scanner) IsString() bool {
+	s.Expect("string")
+	return s.has("\"")
+}
+
+// RawString parses a go raw string enclosed in backquotes, which may span multiple lines.
+// As in go, carriage returns are removed from the string.
+func (s *Scanner) RawString() string {
+	return strings.Replace(s.QuoteMultiline("`", "`", nil), "\r", "", -1)
+}
+
+// IsRawString returns true if the next token is a raw string enclosed in backquotes.
+func (s *Scanner) IsRawString() bool {
+	s.Expect("raw string")
+	return s.has("`")
+}
+
+// GoString parses either a string enclosed in double quotes or a raw string enclosed in backquotes.
+func (s *Scanner) GoString() string {
+	if s.has("`") {
+		return s.RawString()
+	}
+	if !s.has("\"") {
+		s.Fail("string expected")
+		return ""
+	}
+	return s.String()
+}
+
 // Char parses a single rune enclosed in signle quotes.
 func (s *Scanner) Char() rune {
 	q := s.Quote("'", "'", GoEscaper('\''))
@@ -27,7 +57,7 @@ func (s *Scanner) Char() rune {
 // Quote returns all text, including whitespace and comments, between the start and end tokens.
 // Quote causes an error if the current token is not start or if the current line does not contain end.
 func (s *Scanner) Quote(start, end string, esc Escaper) string {
-	if !s.Is(start) {
+	if !s.has(start) {
 		s.Failf("'%s' expected", start)
 		return ""
 	}
@@ -65,8 +95,9 @@ func (s *Scanner) Quote(start, end string, esc Escaper) string {
 
 // QuoteMultiline returns all text, including whitespace, line breaks, and comments, between the start and end tokens.
 // QuoteMultiline causes an error if the current token is not start or if the input does not contain end.
+// Escape sequences are replaced on every line, including lines that do not contain end.
 func (s *Scanner) QuoteMultiline(start, end string, esc Escaper) string {
-	if !s.Is(start) {
+	if !s.has(start) {
 		s.Failf("'%s' expected", start)
 		return ""
 	}
@@ -75,7 +106,7 @@ func (s *Scanner) QuoteMultiline(start, end string, esc Escaper) string {
 	l := strings.Index(s.line[s.pos:], end)
 	if esc != nil {
 		e := esc.EscapeIndex(s.line[s.pos:])
-		for e >= 0 && e < l {
+		for e >= 0 && (l < 0 || e < l) {
 			str, n, err := esc.Unescape(s.line[s.pos+e:])
 			out.WriteString(s.line[s.pos : s.pos+e])
 			out.WriteString(str)
@@ -101,7 +132,7 @@ func (s *Scanner) QuoteMultiline(start, end string, esc Escaper) string {
 		l = strings.Index(s.line[s.pos:], end)
 		if esc != nil {
 			e := esc.EscapeIndex(s.line[s.pos:])
-			for e >= 0 && e < l {
+			for e >= 0 && (l < 0 || e < l) {
 				str, n, err := esc.Unescape(s.line[s.pos+e:])
 				out.WriteString(s.line[s.pos : s.pos+e])
 				out.WriteString(str)
@@ -121,6 +152,135 @@ func (s *Scanner) QuoteMultiline(start, end string, esc Escaper) string {
 	return out.String()
 }
 
+// TripleString parses a string enclosed in triple double quotes, which may span multiple lines.
+// Escape sequences are handled like in String. See TripleQuote for the effect of dedent.
+func (s *Scanner) TripleString(dedent bool) string {
+	return s.TripleQuote(`"""`, GoEscaper('"'), dedent)
+}
+
+// TripleQuote is like QuoteMultiline, but uses the same token as start and end.
+// If dedent is true, the first and last line are removed if they are blank, and the longest common indentation
+// is removed from the remaining lines, as with Kotlin's trimIndent. Escape sequences are replaced after removing
+// the indentation, so escaped whitespace is preserved.
+func (s *Scanner) TripleQuote(quote string, esc Escaper, dedent bool) string {
+	if !dedent {
+		return s.QuoteMultiline(quote, quote, esc)
+	}
+	var raw Escaper
+	if esc != nil {
+		raw = rawEscaper{esc}
+	}
+	text := s.QuoteMultiline(quote, quote, raw)
+	if s.failed() {
+		return ""
+	}
+	text = dedentText(text)
+	if esc != nil {
+		text = unescape(text, esc)
+	}
+	return text
+}
+
+// dedentText removes blank first and last lines and the common indentation of text.
+func dedentText(text string) string {
+	lines := strings.Split(text, "\n")
+	if strings.TrimLeft(lines[0], " \t") == "" {
+		lines = lines[1:]
+	}
+	if n := len(lines); n > 0 && strings.TrimLeft(lines[n-1], " \t") == "" {
+		lines = lines[:n-1]
+	}
+	return strings.Join(dedent(lines), "\n")
+}
+
+// unescape replaces all escape sequences in text, which must be valid.
+func unescape(text string, esc Escaper) string {
+	var out bytes.Buffer
+	for e := esc.EscapeIndex(text); e >= 0; e = esc.EscapeIndex(text) {
+		str, n, err := esc.Unescape(text[e:])
+		if err != nil {
+			break
+		}
+		out.WriteString(text[:e])
+		out.WriteString(str)
+		text = text[e+n:]
+	}
+	out.WriteString(text)
+	return out.String()
+}
+
+// rawEscaper validates escape sequences like the wrapped Escaper, but keeps them unchanged.
+type rawEscaper struct{ Escaper }
+
+func (r rawEscaper) Unescape(s string) (string, int, error) {
+	_, n, err := r.Escaper.Unescape(s)
+	return s[:n], n, err
+}
+
+// A LongQuoteStyle describes quotes whose end token depends on the start token.
+// The start token is Open, followed by any number of repetitions of Fill, followed by OpenEnd.
+// The end token is Close, followed by the same number of repetitions of Fill, followed by CloseEnd.
+type LongQuoteStyle struct {
+	Open, OpenEnd   string
+	Close, CloseEnd string
+	Fill            string
+	// SkipNewline removes a line break directly after the start token.
+	SkipNewline bool
+}
+
+// Common long quote styles.
+var (
+	// LuaBrackets are lua's long brackets, like [[...]] and [==[...]==].
+	LuaBrackets = LongQuoteStyle{Open: "[", OpenEnd: "[", Close: "]", CloseEnd: "]", Fill: "=", SkipNewline: true}
+	// RustRawStrings are rust's raw strings, like r"..." and r#"..."#.
+	RustRawStrings = LongQuoteStyle{Open: "r", OpenEnd: "\"", Close: "\"", Fill: "#"}
+)
+
+// LongBracket parses a lua long bracket string, like [[...]] or [==[...]==], which may span multiple lines.
+func (s *Scanner) LongBracket() string {
+	return s.LongQuote(LuaBrackets)
+}
+
+// IsLongQuote returns true if the next token is the start of a long quote in the given style.
+func (s *Scanner) IsLongQuote(style LongQuoteStyle) bool {
+	s.Expect("string")
+	_, _, ok := style.start(s.line[s.pos:])
+	return ok
+}
+
+// LongQuote returns all text, including whitespace, line breaks, and comments, between a start and end token in the given style.
+// LongQuote causes an error if the current token is not a start token or if the input does not contain the matching end token.
+func (s *Scanner) LongQuote(style LongQuoteStyle) string {
+	line := s.line[s.pos:]
+	n, level, ok := style.start(line)
+	if !ok {
+		s.Failf("'%s' expected", style.Open+style.OpenEnd)
+		return ""
+	}
+	out := s.QuoteMultiline(line[:n], style.Close+strings.Repeat(style.Fill, level)+style.CloseEnd, nil)
+	if style.SkipNewline {
+		out = strings.TrimPrefix(out, "\n")
+	}
+	return out
+}
+
+// start returns the length and level of the start token at the beginning of str,
+// or false if str does not start with a start token.
+func (l LongQuoteStyle) start(str string) (int, int, bool) {
+	if !strings.HasPrefix(str, l.Open) {
+		return 0, 0, false
+	}
+	n, level := len(l.Open), 0
+	for l.Fill != "" && strings.HasPrefix(str[n:], l.Fill) {
+		n += len(l.Fill)
+		level++
+	}
+	if !strings.HasPrefix(str[n:], l.OpenEnd) {
+		return 0, 0, false
+	}
+	return n + len(l.OpenEnd), level, true
+}
+
 // An Escaper can detect and replace escape sequences.
 type Escaper interface {
 	// EscapeIndex returns the index of the first escape sequence in the string, or -1
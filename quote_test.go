@@ -142,6 +142,9 @@ func TestMultilineEscape(t *testing.T) {
 		second line` + "\x00", true},
 		{"[[\\x]]", "", false},
 		{"[[\n\\x]]", "", false},
+		{"[[a\\tb\nc]]", "a\tb\nc", true},
+		{"[[a\n\\x\n]]", "", false},
+		{"[[a\\tb\nc\\td\ne]]", "a\tb\nc\td\ne", true},
 	}
 
 	for _, test := range tests {
@@ -195,3 +198,170 @@ nextToken`, true},
 		}
 	}
 }
+
+func TestRawString(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+		ok  bool
+	}{
+		{"`raw`", "raw", true},
+		{"`\\n`", "\\n", true},
+		{"`a\"b`", "a\"b", true},
+		{"`multi\nline`", "multi\nline", true},
+		{"`crlf\r\nline`", "crlf\nline", true},
+		{"`a\rb`", "ab", true},
+		{"``", "", true},
+		{"`unterminated\n", "", false},
+		{`"string"`, "", false},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		if sc.IsRawString() != (test.in[0] == '`') {
+			t.Errorf("input %q: wrong result from IsRawString", test.in)
+		}
+		out := sc.RawString()
+		if test.ok {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if out != test.out {
+				t.Errorf("input %q produced output %q instead of %q", test.in, out, test.out)
+			}
+		} else {
+			if sc.Err() == nil {
+				t.Errorf("input %q should produce an error", test.in)
+			}
+		}
+	}
+}
+
+func TestGoString(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+		ok  bool
+	}{
+		{`"a\tb"`, "a\tb", true},
+		{"`a\\tb`", "a\\tb", true},
+		{"`a\nb` next", "a\nb", true},
+		{`'a'`, "", false},
+		{`a`, "", false},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		out := sc.GoString()
+		if test.ok {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if out != test.out {
+				t.Errorf("input %q produced output %q instead of %q", test.in, out, test.out)
+			}
+		} else {
+			if sc.Err() == nil {
+				t.Errorf("input %q should produce an error", test.in)
+			}
+		}
+	}
+}
+
+func TestLongQuote(t *testing.T) {
+	tests := []struct {
+		in    string
+		style LongQuoteStyle
+		out   string
+		ok    bool
+	}{
+		{"[[long]]", LuaBrackets, "long", true},
+		{"[==[a]]b]=]c]==]", LuaBrackets, "a]]b]=]c", true},
+		{"[=[\\n]=]", LuaBrackets, "\\n", true},
+		{"[[\nfirst\nsecond]]", LuaBrackets, "first\nsecond", true},
+		{"[[\n\nsecond]]", LuaBrackets, "\nsecond", true},
+		{"[[ \nsecond]]", LuaBrackets, " \nsecond", true},
+		{"[==[a]=]", LuaBrackets, "", false},
+		{"[=a]=]", LuaBrackets, "", false},
+		{"[a]", LuaBrackets, "", false},
+		{`r"raw\"`, RustRawStrings, `raw\`, true},
+		{`r#"a "quoted" b"#`, RustRawStrings, `a "quoted" b`, true},
+		{`r##"a "# b"##`, RustRawStrings, `a "# b`, true},
+		{"r\"\nline\"", RustRawStrings, "\nline", true},
+		{`r#"a"`, RustRawStrings, "", false},
+		{`r#a`, RustRawStrings, "", false},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		if test.ok && !sc.IsLongQuote(test.style) {
+			t.Errorf("input %q: IsLongQuote returned false", test.in)
+		}
+		out := sc.LongQuote(test.style)
+		if test.ok {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if out != test.out {
+				t.Errorf("input %q produced output %q instead of %q", test.in, out, test.out)
+			}
+		} else {
+			if sc.Err() == nil {
+				t.Errorf("input %q should produce an error", test.in)
+			}
+		}
+	}
+
+	sc := FromString("[=[a]=] next")
+	if out := sc.LongBracket(); out != "a" || sc.Ident() != "next" {
+		t.Errorf("LongBracket produced %q, %v", out, sc.Err())
+	}
+	sc = FromString("[==[a]=]\nb")
+	sc.LongBracket()
+	if err, ok := sc.Err().(*Error); !ok || err.Message != "']==]' expected" {
+		t.Errorf("produced error %v", sc.Err())
+	}
+}
+
+func TestTripleQuote(t *testing.T) {
+	tests := []struct {
+		in     string
+		dedent bool
+		out    string
+		ok     bool
+	}{
+		{`"""abc"""`, false, "abc", true},
+		{`"""a"b""c"""`, false, `a"b""c`, true},
+		{`"""a\"""b"""`, false, `a"""b`, true},
+		{"\"\"\"\n  a\n  b\n  \"\"\"", false, "\n  a\n  b\n  ", true},
+		{"\"\"\"\n  a\n  b\n  \"\"\"", true, "a\nb", true},
+		{"\"\"\"\n    a\n      b\n\n    c\n\"\"\"", true, "a\n  b\n\nc", true},
+		{"\"\"\"\n\ta\n\t\tb\n\t\"\"\"", true, "a\n\tb", true},
+		{"\"\"\"  a\n    b\"\"\"", true, "a\n  b", true},
+		{"\"\"\"\n  \\ta\n  b\n\"\"\"", true, "\ta\nb", true},
+		{"\"\"\"\n\\t  a\n  b\n\"\"\"", true, "\t  a\n  b", true},
+		{"\"\"\"\n  a\\\"\"\"\n\"\"\"", true, `a"""`, true},
+		{`""""""`, true, "", true},
+		{"\"\"\"\n\n\"\"\"", true, "", true},
+		{"\"\"\"\n  \\x\n\"\"\"", true, "", false},
+		{"\"\"\"\n  a\n", true, "", false},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		out := sc.TripleString(test.dedent)
+		if test.ok {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			} else if out != test.out {
+				t.Errorf("input %q produced output %q instead of %q", test.in, out, test.out)
+			}
+		} else {
+			if sc.Err() == nil {
+				t.Errorf("input %q should produce an error", test.in)
+			}
+		}
+	}
+
+	sc := FromString("'''\n  raw \\n\n  '''")
+	if out := sc.TripleQuote("'''", nil, true); out != `raw \n` {
+		t.Errorf("produced %q", out)
+	}
+}
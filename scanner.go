@@ -11,20 +11,112 @@ import (
 )
 
 // A Scanner wraps an io.Reader and provides convenient methods for parsing simple languages.
-// The scanner will ignore whitespace and go-style comments, except to seperate tokens.
+// The scanner will ignore whitespace and comments, except to seperate tokens.
 // Once the scanner encounters any error, most of it's methods will return the zero value.
 type Scanner struct {
-	source  *bufio.Scanner
+	source  *bufio.Reader
 	line    string
 	pos, ln int
 	r       rune
 	size    int
 	err     error
+	opt     Options
+	off     int // offset of the current line
+	srcOff  int // number of bytes read from source
+
+	keywords map[string]bool
+	srcErr   error // error from reading the source, which can not be undone by Reset
+
+	buf   []bufLine // lines following line bufLn, kept for Reset
+	bufLn int
+	marks int
+	skip  int // number of lines following the current one that were consumed by Heredoc
+
+	errs     ErrorList
+	syncing  bool   // an error was reported in recovery mode, see Sync
+	syncLine string // line and position at which the error was reported
+	syncPos  int
+
+	expected []expectation // see Expect
+	expOff   int
+
+	colLine          string // line, byte position and number of preceding runes of the last call to Pos
+	colPos, colRunes int
+}
+
+type bufLine struct {
+	text string
+	off  int
+}
+
+// Options configures the syntax recognized by a scanner.
+// The zero value describes a language without comments.
+type Options struct {
+	// LineComments lists strings that start a comment reaching to the end of the line.
+	LineComments []string
+	// BlockComments lists pairs of delimiters that enclose a comment.
+	// If the start delimiters of several line or block comments match, the longest one is used.
+	BlockComments []BlockComment
+	// NestedComments causes block comments to nest, so that every start delimiter inside a block comment
+	// must be matched by its own end delimiter.
+	NestedComments bool
+	// Filename is the file name reported in positions and errors.
+	Filename string
+	// MaxLineLength limits the length of input lines in bytes, not counting the line terminator.
+	// Longer lines cause an error. If MaxLineLength is 0, lines may have any length.
+	MaxLineLength int
+	// Numbers enables optional features of number literals.
+	Numbers NumberSyntax
+	// Ident defines which runes identifiers may consist of. If Ident.Start is nil, GoIdent is used.
+	Ident IdentRules
+	// Keywords lists reserved words, which are not accepted as identifiers by Ident.
+	Keywords []string
+	// FoldCase causes Is, Eat, Demand, the keyword methods and the Keywords option to ignore case,
+	// using Unicode simple case folding like strings.EqualFold.
+	FoldCase bool
+	// QualifiedNoSpace causes QualifiedIdent to reject whitespace and comments around the separators.
+	QualifiedNoSpace bool
+	// Recover enables error recovery: instead of stopping at the first error,
+	// the scanner collects all errors reported with Fail. See Scanner.Sync.
+	Recover bool
+}
+
+// A BlockComment describes a comment that starts with Start and ends with End.
+type BlockComment struct {
+	Start, End string
+}
+
+// DefaultOptions returns the options used by New, which recognize go-style comments and number literals.
+func DefaultOptions() Options {
+	return Options{
+		LineComments:  []string{"//"},
+		BlockComments: []BlockComment{{"/*", "*/"}},
+		Numbers:       GoNumbers,
+	}
 }
 
 // New creates a scanner that will read from an io.Reader
 func New(in io.Reader) *Scanner {
-	s := &Scanner{source: bufio.NewScanner(in)}
+	return NewWithOptions(in, DefaultOptions())
+}
+
+// NewWithOptions creates a scanner that will read from an io.Reader and use the given options.
+func NewWithOptions(in io.Reader, opt Options) *Scanner {
+	s := &Scanner{source: bufio.NewReader(in), opt: opt}
+	if opt.Ident.Start == nil {
+		s.opt.Ident = GoIdent
+	} else if opt.Ident.Part == nil {
+		s.opt.Ident.Part = opt.Ident.Start
+	}
+	if len(opt.Keywords) > 0 {
+		s.keywords = make(map[string]bool)
+		for _, k := range opt.Keywords {
+			if opt.FoldCase {
+				k = foldKey(k)
+			}
+			s.keywords[k] = true
+		}
+	}
 	s.space()
 	return s
 }
@@ -37,28 +129,102 @@ func FromString(s string) *Scanner {
 func (s *Scanner) next() {
 	s.pos += s.size
 	s.r, s.size = 0, 0
-	if s.err != nil {
+	if s.err != nil || s.syncing {
 		return
 	}
 	if s.pos < len(s.line) {
 		s.r, s.size = utf8.DecodeRuneInString(s.line[s.pos:])
 	} else {
-		if !s.source.Scan() {
-			s.err = s.source.Err()
+		for ; s.skip > 0; s.skip-- {
+			if _, ok := s.readLine(); !ok {
+				break
+			}
+			s.ln++
+		}
+		line, ok := s.readLine()
+		if !ok {
 			if s.err == nil {
 				s.err = io.EOF
 			}
-			s.line = ""
-			s.pos = 0
+			// keep the last line, so that positions at the end of the input point after its last character
+			s.pos = len(s.line)
 			return
 		}
-		s.line = s.source.Text()
+		s.line, s.off = line.text, line.off
 		s.ln++
 		s.pos = 0
 		s.r = '\n'
 	}
 }
 
+// readLine returns the line following the current one, either from the replay buffer or from the source.
+func (s *Scanner) readLine() (bufLine, bool) {
+	if i := s.ln - s.bufLn; i < len(s.buf) {
+		line := s.buf[i]
+		if s.marks == 0 {
+			s.buf = s.buf[i+1:]
+			s.bufLn = s.ln + 1
+		}
+		return line, true
+	}
+	off := s.srcOff
+	text, ok := s.readSource(s.ln + 1)
+	if !ok {
+		return bufLine{}, false
+	}
+	line := bufLine{text, off}
+	if s.marks > 0 {
+		s.buf = append(s.buf, line)
+	}
+	return line, true
+}
+
+// readSource reads line number ln from the underlying reader, without the line terminator.
+// If no line could be read, readSource sets the scanner's error unless the input has simply ended.
+func (s *Scanner) readSource(ln int) (string, bool) {
+	var line []byte
+	for {
+		frag, err := s.source.ReadSlice('\n')
+		line = append(line, frag...)
+		if err == bufio.ErrBufferFull {
+			if max := s.opt.MaxLineLength; max > 0 && len(line) > max+1 {
+				s.srcErr = s.lineTooLong(string(line[:max]), ln)
+				s.err = s.srcErr
+				return "", false
+			}
+			continue
+		}
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			if err != io.EOF {
+				s.srcErr = err
+				s.err = err
+			}
+			return "", false
+		}
+		break
+	}
+	raw := len(line)
+	if n := len(line); n > 0 && line[n-1] == '\n' {
+		line = line[:n-1]
+	}
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	if max := s.opt.MaxLineLength; max > 0 && len(line) > max {
+		s.srcErr = s.lineTooLong(string(line[:max]), ln)
+		s.err = s.srcErr
+		return "", false
+	}
+	s.srcOff += raw
+	return string(line), true
+}
+
+// lineTooLong returns an error pointing at the end of prefix, which is the beginning of line number ln.
+func (s *Scanner) lineTooLong(prefix string, ln int) *Error {
+	pos := Position{s.opt.Filename, s.srcOff + len(prefix), ln, utf8.RuneCountInString(prefix) + 1}
+	return &Error{"line too long", prefix, pos}
+}
+
 func (s *Scanner) update() {
 	s.size = 0
 	s.next()
@@ -70,25 +236,53 @@ repeat:
 	for unicode.IsSpace(s.r) {
 		s.next()
 	}
-	if s.Is("//") {
-		s.pos = len(s.line)
-		s.next()
-		goto repeat
-	} else if s.Is("/*") {
-		err := &Error{"unmatched '/*'", s.line, s.ln, s.pos}
-		s.pos += 2
-		for s.err == nil {
-			end := strings.Index(s.line[s.pos:], "*/")
-			if end >= 0 {
-				s.pos += end + 2
-				goto repeat
-			}
+	// if several delimiters match, the longest one is used, like "--[[" over "--" in lua
+	longest, block := 0, -1
+	for _, c := range s.opt.LineComments {
+		if len(c) > longest && s.has(c) {
+			longest = len(c)
+		}
+	}
+	for i, c := range s.opt.BlockComments {
+		if c.Start != "" && len(c.Start) >= longest && s.has(c.Start) {
+			longest, block = len(c.Start), i
+		}
+	}
+	if block < 0 {
+		if longest > 0 {
 			s.pos = len(s.line)
-			s.update()
+			s.next()
+			goto repeat
 		}
-		if s.err == io.EOF {
-			s.err = err
+		return
+	}
+	c := s.opt.BlockComments[block]
+	err := s.errorAt(fmt.Sprintf("unmatched '%s'", c.Start))
+	s.pos += len(c.Start)
+	depth := 1
+	for s.err == nil {
+		end := strings.Index(s.line[s.pos:], c.End)
+		if s.opt.NestedComments {
+			start := strings.Index(s.line[s.pos:], c.Start)
+			if start >= 0 && (end < 0 || start < end) {
+				s.pos += start + len(c.Start)
+				depth++
+				continue
+			}
+		}
+		if end >= 0 {
+			s.pos += end + len(c.End)
+			depth--
+			if depth == 0 {
+				goto repeat
+			}
+			continue
 		}
+		s.pos = len(s.line)
+		s.update()
+	}
+	if s.err == io.EOF {
+		s.err = err
 	}
 }
 
@@ -114,20 +308,26 @@ func (s *Scanner) Rune() rune {
 
 // Is returns true if the scanner's input starts with str, but does not advance the scanner.
 // str should not contain whitespace.
+// If the FoldCase option is set, Is ignores case.
 func (s *Scanner) Is(str string) bool {
+	if s.opt.FoldCase {
+		return s.IsFold(str)
+	}
+	s.expectToken(str)
+	return s.has(str)
+}
+
+// has is like Is, but does not record str as expected.
+func (s *Scanner) has(str string) bool {
 	return strings.HasPrefix(s.line[s.pos:], str)
 }
 
 // Eat returns true and consumes the string if the scanner's input starts with str.
 // The scanner will not be advanced if Eat returns false.
 // str should not contain whitespace.
+// If the FoldCase option is set, Eat ignores case.
 func (s *Scanner) Eat(str string) bool {
-	if s.Is(str) {
-		s.pos += len(str)
-		s.space()
-		return true
-	}
-	return false
+	return s.eatMatch(str, s.opt.FoldCase)
 }
 
 // Demand consumes a string, or causes an error if the scanner's input does not start with str.
@@ -140,9 +340,22 @@ func (s *Scanner) Demand(str string) {
 
 // Fail sets the scanner's error, unless it has already encountered another error.
 // The error will contain the current line and position of the scanner.
+//
+// If the scanner was created with the Recover option, Fail instead adds the error to the list returned by Err.
+// Until Sync is called, the scanner behaves as if it had encountered an error, and further calls to Fail are ignored.
 func (s *Scanner) Fail(msg string) {
 	if s.err == nil || s.err == io.EOF {
-		s.err = &Error{msg, s.line, s.ln, s.pos}
+		if s.syncing {
+			return
+		}
+		err := s.errorAt(msg)
+		if s.opt.Recover {
+			s.errs = append(s.errs, err)
+			s.syncing = true
+			s.syncLine, s.syncPos = s.line, s.pos
+		} else {
+			s.err = err
+		}
 		s.line = ""
 		s.pos = 0
 		s.r = 0
@@ -152,17 +365,42 @@ func (s *Scanner) Fail(msg string) {
 // Failf sets the scanner's error, unless it has already encountered another error.
 // The error will contain the current line and position of the scanner.
 func (s *Scanner) Failf(format string, a ...interface{}) {
-	if s.err == nil || s.err == io.EOF {
-		s.err = &Error{fmt.Sprintf(format, a...), s.line, s.ln, s.pos}
-		s.line = ""
-		s.pos = 0
-		s.r = 0
+	s.Fail(fmt.Sprintf(format, a...))
+}
+
+// Sync recovers from an error reported with Fail.
+// It skips input, starting at the position of the error, until the input starts with one of the tokens
+// or until the end of the input. The token itself is not consumed. After Sync, calls to Fail will record errors again.
+// If no error was reported since the last call to Sync, Sync does nothing.
+// Sync is only useful if the scanner was created with the Recover option.
+func (s *Scanner) Sync(tokens ...string) {
+	if !s.syncing {
+		return
+	}
+	s.syncing = false
+	s.line, s.pos = s.syncLine, s.syncPos
+	s.space()
+	for s.err == nil {
+		for _, t := range tokens {
+			if s.has(t) {
+				return
+			}
+		}
+		s.Rune()
 	}
 }
 
 // Err returns the first error encountered by the scanner, or nil if there was no error.
 // The returned error will either be of the type *Error, or an error returned by the underlying io.Reader.
+// If the scanner was created with the Recover option, syntax errors are instead returned as an ErrorList.
 func (s *Scanner) Err() error {
+	if len(s.errs) > 0 {
+		if err, ok := s.err.(*Error); ok {
+			return append(s.errs[:len(s.errs):len(s.errs)], err)
+		} else if s.err == nil || s.err == io.EOF {
+			return s.errs
+		}
+	}
 	if s.err == io.EOF {
 		return nil
 	}
@@ -171,15 +409,27 @@ func (s *Scanner) Err() error {
 
 // End returns true if the scanner has reached the end of the input or encountered an error.
 func (s *Scanner) End() bool {
-	return s.err != nil
+	return s.err != nil || s.syncing
+}
+
+// failed returns true if the scanner has encountered an error other than the end of the input.
+func (s *Scanner) failed() bool {
+	return s.err != nil && s.err != io.EOF || s.syncing
+}
+
+// errorAt returns an error at the current position.
+func (s *Scanner) errorAt(msg string) *Error {
+	return &Error{msg, s.line, s.Pos()}
 }
 
 // Error is an error type that contains a reference to a specific position in a scanners input.
+//
+// Warning: e.Line is the text of the line containing the error, not its number.
+// It shadows the field Line of the embedded Position, so the line number is e.Position.Line.
 type Error struct {
 	Message  string
-	Line     string
-	LineNum  int
-	Position int
+	Line     string // the text of the line containing the error
+	Position        // the position of the error; its line number is e.Position.Line
 }
 
 // Error returns the errors message.
@@ -187,11 +437,31 @@ func (e *Error) Error() string {
 	return "scanner: " + e.Message
 }
 
+// ErrorList is a list of errors, returned by Scanner.Err if the Recover option is enabled.
+type ErrorList []*Error
+
+// Error returns the message of the first error and the number of further errors.
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0].Error(), len(l)-1)
+}
+
 // PositionIndicator returns a user-friendly multi-line-string containing the message, line and position of the error.
+// The line containing the caret copies tabs from the input line and accounts for the display width of other characters,
+// so that the caret appears below the erroneous character when printed to a terminal.
 func (e *Error) PositionIndicator() string {
-	if e.Position > 0 {
-		// TODO account for tabs
-		return fmt.Sprint("Line ", e.LineNum, ": ", e.Message, "\n", e.Line, "\n", strings.Repeat(" ", e.Position-1), "^")
+	var pad strings.Builder
+	for _, r := range e.Line[:columnIndex(e.Line, e.Column)] {
+		if r == '\t' {
+			pad.WriteByte('\t')
+		} else {
+			pad.WriteString("  "[:runeWidth(r)])
+		}
 	}
-	return fmt.Sprint("Line ", e.LineNum, ": ", e.Message, "\n", e.Line, "\n", "^")
+	return fmt.Sprint("Line ", e.Position.Line, ": ", e.Message, "\n", e.Line, "\n", pad.String(), "^")
 }
@@ -1,6 +1,8 @@
 package scanner
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -86,3 +88,202 @@ func TestDemand(t *testing.T) {
 		}
 	}
 }
+
+func TestComments(t *testing.T) {
+	tests := []struct {
+		in      string
+		opt     Options
+		demands []string
+		ok      bool
+	}{
+		{"a // b\n c", DefaultOptions(), []string{"a", "c"}, true},
+		{"a # b\n c", DefaultOptions(), []string{"a", "#", "b", "c"}, true},
+		{"a # b\n c", Options{LineComments: []string{"#"}}, []string{"a", "c"}, true},
+		{"a ; b\n -- c\n d", Options{LineComments: []string{";", "--"}}, []string{"a", "d"}, true},
+		{"a (* b \n *) c", Options{BlockComments: []BlockComment{{"(*", "*)"}}}, []string{"a", "c"}, true},
+		{"a (* b", Options{BlockComments: []BlockComment{{"(*", "*)"}}}, []string{"a"}, false},
+		{"a // b /* c */ d", Options{}, []string{"a", "//", "b", "/*", "c", "*/", "d"}, true},
+		{"a // b", Options{BlockComments: []BlockComment{{"/*", "*/"}}}, []string{"a", "//", "b"}, true},
+		{"a --[[ multi\nline ]] b", Options{LineComments: []string{"--"}, BlockComments: []BlockComment{{"--[[", "]]"}}}, []string{"a", "b"}, true},
+		{"a -- [[ b\n c", Options{LineComments: []string{"--"}, BlockComments: []BlockComment{{"--[[", "]]"}}}, []string{"a", "c"}, true},
+		{"a --[[ b", Options{LineComments: []string{"--"}, BlockComments: []BlockComment{{"--[[", "]]"}}}, []string{"a"}, false},
+		{"a //* b\n c", Options{LineComments: []string{"//*"}, BlockComments: []BlockComment{{"/*", "*/"}}}, []string{"a", "c"}, true},
+	}
+	for _, test := range tests {
+		sc := NewWithOptions(strings.NewReader(test.in), test.opt)
+		for _, d := range test.demands {
+			sc.Demand(d)
+		}
+		if sc.Err() == nil {
+			if !test.ok {
+				t.Errorf("input %q, %q should produce an error", test.in, test.demands)
+			} else if !sc.End() {
+				t.Errorf("input %q, %q: End returned false", test.in, test.demands)
+			}
+		} else {
+			if test.ok {
+				t.Errorf("input %q, %q produced error: %s", test.in, test.demands, sc.Err())
+			}
+		}
+	}
+}
+
+func TestNestedComments(t *testing.T) {
+	nested := DefaultOptions()
+	nested.NestedComments = true
+	tests := []struct {
+		in      string
+		opt     Options
+		demands []string
+		ok      bool
+	}{
+		{"a /* /* b */ c */ d", nested, []string{"a", "d"}, true},
+		{"a /* /* b */ c */ d", DefaultOptions(), []string{"a", "c", "*/", "d"}, true},
+		{"a /* /*\n b */ \n c */ d", nested, []string{"a", "d"}, true},
+		{"a /**/ b /*/**/*/ c", nested, []string{"a", "b", "c"}, true},
+		{"a /* /* b */ c", nested, []string{"a"}, false},
+	}
+	for _, test := range tests {
+		sc := NewWithOptions(strings.NewReader(test.in), test.opt)
+		for _, d := range test.demands {
+			sc.Demand(d)
+		}
+		if sc.Err() == nil {
+			if !test.ok {
+				t.Errorf("input %q, %q should produce an error", test.in, test.demands)
+			} else if !sc.End() {
+				t.Errorf("input %q, %q: End returned false", test.in, test.demands)
+			}
+		} else {
+			if test.ok {
+				t.Errorf("input %q, %q produced error: %s", test.in, test.demands, sc.Err())
+			}
+		}
+	}
+
+	sc := NewWithOptions(strings.NewReader("a\n  /* b\n /* c */\n"), nested)
+	sc.Demand("a")
+	if err, ok := sc.Err().(*Error); !ok {
+		t.Errorf("unmatched comment produced error %v", sc.Err())
+	} else if err.Position.Line != 2 || err.Column != 3 || err.Offset != 4 {
+		t.Errorf("unmatched comment reported at %s (offset %d) instead of 2:3 (offset 4)", err.Position, err.Offset)
+	}
+}
+
+func TestLongLines(t *testing.T) {
+	long := strings.Repeat("a", 100000)
+	sc := FromString("b\n" + long + "\r\nc")
+	sc.Demand("b")
+	if out := sc.Ident(); out != long {
+		t.Errorf("long line produced output of length %d instead of %d", len(out), len(long))
+	}
+	sc.Demand("c")
+	if sc.Err() != nil {
+		t.Errorf("long line produced error: %s", sc.Err())
+	}
+
+	tests := []struct {
+		in string
+		ok bool
+	}{
+		{"aaaa\naaaaa\r\n", true},
+		{"aaaa\naaaaa", true},
+		{"aaaa\naaaaaa\n", false},
+		{"aaaa\naaaaaa", false},
+		{"aaaa\n" + long, false},
+	}
+	for _, test := range tests {
+		sc := NewWithOptions(strings.NewReader(test.in), Options{MaxLineLength: 5})
+		for !sc.End() {
+			sc.Ident()
+		}
+		if test.ok {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			}
+		} else if err, ok := sc.Err().(*Error); !ok {
+			t.Errorf("input %q produced error %v", test.in, sc.Err())
+		} else if err.Position.Line != 2 {
+			t.Errorf("input %q produced error in line %d instead of 2", test.in, err.Position.Line)
+		}
+	}
+}
+
+func TestPositionIndicator(t *testing.T) {
+	tests := []struct {
+		in   string
+		skip int
+		out  string
+	}{
+		{"abc", 0, "Line 1: test\nabc\n^"},
+		{"a bc", 1, "Line 1: test\na bc\n  ^"},
+		{"\ta\tbc", 1, "Line 1: test\n\ta\tbc\n\t \t^"},
+		{"äöü x", 3, "Line 1: test\näöü x\n    ^"},
+		{"日本 x", 2, "Line 1: test\n日本 x\n     ^"},
+		{"e\u0301 x", 2, "Line 1: test\ne\u0301 x\n  ^"},
+		{"a\n  b", 1, "Line 2: test\n  b\n  ^"},
+	}
+	for _, test := range tests {
+		sc := FromString(test.in)
+		for i := 0; i < test.skip; i++ {
+			sc.Rune()
+		}
+		sc.Fail("test")
+		if out := sc.Err().(*Error).PositionIndicator(); out != test.out {
+			t.Errorf("input %q produced\n%s\ninstead of\n%s", test.in, out, test.out)
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		in    string
+		names []string
+		lines []int
+	}{
+		{"a = 1; b = 2;", []string{"a", "b"}, nil},
+		{"a = 1;\nb = ;\nc = 3;", []string{"a", "b", "c"}, []int{2}},
+		{"a = 1;\nb = ;\nc 3;\nd = 4;", []string{"a", "b", "c", "d"}, []int{2, 3}},
+		{"a = x y z;\nb = 2;", []string{"a", "b"}, []int{1}},
+		{"a = \"x;\nb = 2;\nc = 3;", []string{"a", "c"}, []int{1}},
+		{"a = 1\nb = 2;\nc = 3\nd = 4;", []string{"a", "c"}, []int{2, 4}},
+		{"a = 1", []string{"a"}, []int{1}},
+	}
+	for _, test := range tests {
+		sc := NewWithOptions(strings.NewReader(test.in), Options{Recover: true})
+		var names []string
+		for !sc.End() {
+			names = append(names, sc.Ident())
+			sc.Demand("=")
+			if sc.Is("\"") {
+				sc.Quote("\"", "\"", nil)
+			} else {
+				sc.Int()
+			}
+			sc.Demand(";")
+			sc.Sync(";")
+			sc.Eat(";")
+		}
+		if strings.Join(names, " ") != strings.Join(test.names, " ") {
+			t.Errorf("input %q produced names %q instead of %q", test.in, names, test.names)
+		}
+		if test.lines == nil {
+			if sc.Err() != nil {
+				t.Errorf("input %q produced error: %s", test.in, sc.Err())
+			}
+			continue
+		}
+		errs, ok := sc.Err().(ErrorList)
+		if !ok {
+			t.Errorf("input %q produced error %v", test.in, sc.Err())
+			continue
+		}
+		lines := make([]int, len(errs))
+		for i, err := range errs {
+			lines[i] = err.Position.Line
+		}
+		if fmt.Sprint(lines) != fmt.Sprint(test.lines) {
+			t.Errorf("input %q produced errors in lines %v instead of %v", test.in, lines, test.lines)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package scanner
+
+import (
+	"unicode"
+)
+
+// A TokenKind classifies the tokens returned by a Tokenizer.
+type TokenKind int
+
+// The kinds of tokens returned by a Tokenizer.
+const (
+	EOFToken TokenKind = iota
+	IdentToken
+	IntToken
+	FloatToken
+	StringToken
+	CharToken
+	PunctToken
+	KeywordToken
+	ImaginaryToken
+)
+
+var tokenKinds = [...]string{"end of input", "identifier", "integer", "float", "string", "character", "punctuation", "keyword", "imaginary number"}
+
+// String returns a description of the token kind.
+func (k TokenKind) String() string {
+	if k < 0 || int(k) >= len(tokenKinds) {
+		return "invalid token"
+	}
+	return tokenKinds[k]
+}
+
+// A Token is a single token returned by a Tokenizer.
+type Token struct {
+	Kind TokenKind
+	// Text is the token as it appears in the input.
+	// For strings and characters, Text contains the unquoted value instead.
+	Text string
+	Pos  Position
+}
+
+// A Tokenizer splits a scanner's input into tokens.
+type Tokenizer struct {
+	sc  *Scanner
+	ops *OperatorSet
+}
+
+// NewTokenizer creates a tokenizer that reads from a scanner.
+// The operators are returned as a single PunctToken, the longest matching operator is always used.
+// Any other rune that does not start a different token is returned as a PunctToken on its own.
+func NewTokenizer(sc *Scanner, operators ...string) *Tokenizer {
+	return &Tokenizer{sc, NewOperatorSet(operators...)}
+}
+
+// NewTokenizerWithOperators creates a tokenizer that reads from a scanner and uses a precompiled set of operators.
+func NewTokenizerWithOperators(sc *Scanner, ops *OperatorSet) *Tokenizer {
+	return &Tokenizer{sc, ops}
+}
+
+// Next returns the next token.
+// At the end of the input, or if the scanner has encountered an error, Next returns a token of kind EOFToken.
+func (t *Tokenizer) Next() Token {
+	s := t.sc
+	pos := s.Pos()
+	switch {
+	case s.End():
+		return Token{EOFToken, "", pos}
+	case s.isIdent():
+		if w := s.peekWord(); s.isKeyword(w) {
+			return t.take(KeywordToken, len(w), pos)
+		}
+		return Token{IdentToken, s.Ident(), pos}
+	case s.has("\""):
+		return t.token(StringToken, s.String(), pos)
+	case s.has("'"):
+		return t.token(CharToken, string(s.Char()), pos)
+	case t.isNumber():
+		switch text, kind, _ := s.peekNumber(); {
+		case text == "":
+		case kind == IntNumber:
+			return t.take(IntToken, len(text), pos)
+		case kind == FloatNumber:
+			return t.take(FloatToken, len(text), pos)
+		default:
+			return t.take(ImaginaryToken, len(text), pos)
+		}
+		s.Fail("invalid number")
+		return Token{EOFToken, "", pos}
+	}
+	if n := t.ops.Match(s.line[s.pos:]); n > 0 {
+		return t.take(PunctToken, n, pos)
+	}
+	return t.take(PunctToken, s.size, pos)
+}
+
+// isNumber returns true if the next token starts with a digit, ignoring signs.
+func (t *Tokenizer) isNumber() bool {
+	s := t.sc
+	if s.r == '.' && s.pos+1 < len(s.line) {
+		return unicode.IsDigit(rune(s.line[s.pos+1]))
+	}
+	return unicode.IsDigit(s.r)
+}
+
+// take consumes n bytes and returns them as a token.
+func (t *Tokenizer) take(kind TokenKind, n int, pos Position) Token {
+	s := t.sc
+	text := s.line[s.pos : s.pos+n]
+	s.pos += n
+	s.space()
+	return Token{kind, text, pos}
+}
+
+// token returns a token, unless the scanner has encountered an error.
+func (t *Tokenizer) token(kind TokenKind, text string, pos Position) Token {
+	if t.sc.failed() {
+		return Token{EOFToken, "", pos}
+	}
+	return Token{kind, text, pos}
+}
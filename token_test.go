@@ -0,0 +1,84 @@
+package scanner_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/jfreymuth/scanner"
+)
+
+func TestTokenizer(t *testing.T) {
+	tests := []struct {
+		in  string
+		ops []string
+		out []string
+		ok  bool
+	}{
+		{"", nil, nil, true},
+		{"a b", nil, []string{"identifier a", "identifier b"}, true},
+		{"x = 12 + 0.5", nil, []string{"identifier x", "punctuation =", "integer 12", "punctuation +", "float 0.5"}, true},
+		{`f("s", 'c')`, nil, []string{"identifier f", "punctuation (", "string s", "punctuation ,", "character c", "punctuation )"}, true},
+		{"a-1", nil, []string{"identifier a", "punctuation -", "integer 1"}, true},
+		{"0x1F 0b1 1_0 0x1p-2 2.5i", nil, []string{"integer 0x1F", "integer 0b1", "integer 1_0", "float 0x1p-2", "imaginary number 2.5i"}, true},
+		{".5 .a", nil, []string{"float .5", "punctuation .", "identifier a"}, true},
+		{"a<<=b<=c", nil, []string{"identifier a", "punctuation <", "punctuation <", "punctuation =", "identifier b", "punctuation <", "punctuation =", "identifier c"}, true},
+		{"a<<=b<=c", []string{"<", "<=", "<<", "<<="}, []string{"identifier a", "punctuation <<=", "identifier b", "punctuation <=", "identifier c"}, true},
+		{"a // comment\n/* comment */ b", nil, []string{"identifier a", "identifier b"}, true},
+		{"\"abc", nil, nil, false},
+		{"a 1x", nil, []string{"identifier a"}, false},
+		{"a 1__0", nil, []string{"identifier a"}, false},
+		{"a 1_", nil, []string{"identifier a"}, false},
+		{"a 0x1.8", nil, []string{"identifier a"}, false},
+	}
+
+	for _, test := range tests {
+		sc := FromString(test.in)
+		tok := NewTokenizer(sc, test.ops...)
+		var out []string
+		for {
+			t := tok.Next()
+			if t.Kind == EOFToken {
+				break
+			}
+			out = append(out, fmt.Sprintf("%s %s", t.Kind, t.Text))
+		}
+		if strings.Join(out, "|") != strings.Join(test.out, "|") {
+			t.Errorf("input %q produced %q instead of %q", test.in, out, test.out)
+		}
+		if test.ok && sc.Err() != nil {
+			t.Errorf("input %q produced error: %s", test.in, sc.Err())
+		} else if !test.ok && sc.Err() == nil {
+			t.Errorf("input %q should produce an error", test.in)
+		}
+	}
+}
+
+func TestTokenKeyword(t *testing.T) {
+	sc := NewWithOptions(strings.NewReader("if iff"), Options{Keywords: []string{"if"}})
+	tok := NewTokenizer(sc)
+	if t1, t2 := tok.Next(), tok.Next(); t1.Kind != KeywordToken || t1.Text != "if" || t2.Kind != IdentToken || t2.Text != "iff" {
+		t.Errorf("produced %v %q, %v %q", t1.Kind, t1.Text, t2.Kind, t2.Text)
+	}
+}
+
+func TestTokenPos(t *testing.T) {
+	tok := NewTokenizer(FromString("a\n  \"b\" 3"))
+	for _, p := range []Position{{"", 0, 1, 1}, {"", 4, 2, 3}, {"", 8, 2, 7}, {"", 9, 2, 8}} {
+		if pos := tok.Next().Pos; pos != p {
+			t.Errorf("token at %v (offset %d) instead of %v (offset %d)", pos, pos.Offset, p, p.Offset)
+		}
+	}
+}
+
+func TestTokenLongLine(t *testing.T) {
+	// positions are computed incrementally, so this does not take quadratic time
+	tok := NewTokenizer(FromString(strings.Repeat("a,", 100000) + "ä b"))
+	var last Token
+	for tk := tok.Next(); tk.Kind != EOFToken; tk = tok.Next() {
+		last = tk
+	}
+	if p := (Position{"", 200003, 1, 200003}); last.Text != "b" || last.Pos != p {
+		t.Errorf("last token %q at %v (offset %d) instead of \"b\" at %v", last.Text, last.Pos, last.Pos.Offset, p)
+	}
+}